@@ -2,69 +2,358 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
-	"github.com/zalando/go-keyring"
+	"github.com/lsherman98/yt-rss-cli/credentials"
 )
 
+// DefaultBaseURL is the public ytrss.xyz API.
+const DefaultBaseURL = "https://ytrss.xyz/api/v1"
+
+// InsecureDefaultBaseURL is the plain HTTP address used before HTTPS became
+// the default. It is only used when insecure HTTP is allowed and no base URL
+// is configured.
+const InsecureDefaultBaseURL = "http://ytrss.xyz/api/v1"
+
+// Item statuses reported by the API. CREATED means the video is still being
+// processed; SUCCESS and ERROR are terminal.
 const (
-	BaseURL     = "http://ytrss.xyz/api/v1"
-	serviceName = "ytrss-cli"
+	StatusCreated = "CREATED"
+	StatusSuccess = "SUCCESS"
+	StatusError   = "ERROR"
 )
 
-var apiClient = NewAPIClient(BaseURL)
+var apiClient = NewAPIClient(DefaultBaseURL)
+
+// SetDefaultClient replaces the client used by the package-level functions,
+// for example to point them at another server.
+func SetDefaultClient(c *APIClient) {
+	apiClient = c
+}
 
+// Podcast is a podcast as listed by the server. Only ID and Title were
+// read by the original client; the other fields are sent and read by the
+// create and update calls and are empty when the server omits them.
 type Podcast struct {
-	ID    string `json:"id"`
-	Title string `json:"title"`
+	ID          string `json:"id" yaml:"id"`
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Author      string `json:"author,omitempty" yaml:"author,omitempty"`
+	// Artwork is the URL of the cover image.
+	Artwork  string `json:"artwork,omitempty" yaml:"artwork,omitempty"`
+	Category string `json:"category,omitempty" yaml:"category,omitempty"`
+}
+
+type CreatePodcastRequestBody struct {
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Author      string `json:"author,omitempty" yaml:"author,omitempty"`
+	Artwork     string `json:"artwork,omitempty" yaml:"artwork,omitempty"`
+	Category    string `json:"category,omitempty" yaml:"category,omitempty"`
+}
+
+// UpdatePodcastRequestBody changes the fields that are set and leaves the
+// nil ones as they are. An empty string clears a field.
+type UpdatePodcastRequestBody struct {
+	PodcastID   string  `json:"podcast_id" yaml:"podcast_id"`
+	Title       *string `json:"title,omitempty" yaml:"title,omitempty"`
+	Description *string `json:"description,omitempty" yaml:"description,omitempty"`
+	Author      *string `json:"author,omitempty" yaml:"author,omitempty"`
+	Artwork     *string `json:"artwork,omitempty" yaml:"artwork,omitempty"`
+	Category    *string `json:"category,omitempty" yaml:"category,omitempty"`
+}
+
+type DeletePodcastRequestBody struct {
+	PodcastID string `json:"podcast_id" yaml:"podcast_id"`
 }
 
 type AddUrlRequestBody struct {
-	PodcastID string `json:"podcast_id"`
-	URL       string `json:"url"`
+	PodcastID string `json:"podcast_id" yaml:"podcast_id"`
+	URL       string `json:"url" yaml:"url"`
 }
 
 type Job struct {
-	Status  string `json:"status"`
-	Title   string `json:"title,omitempty"`
-	Created string `json:"created,omitempty"`
-	Error   string `json:"error,omitempty"`
+	Status  string `json:"status" yaml:"status"`
+	Title   string `json:"title,omitempty" yaml:"title,omitempty"`
+	Created string `json:"created,omitempty" yaml:"created,omitempty"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
 }
 
 type UsageResponse struct {
-	Usage int `json:"usage"`
-	Limit int `json:"limit"`
+	Usage int `json:"usage" yaml:"usage"`
+	Limit int `json:"limit" yaml:"limit"`
 }
 
 type Item struct {
-	Status  string `json:"status"`
-	Title   string `json:"title,omitempty"`
-	Error   string `json:"error,omitempty"`
-	Created string `json:"created,omitempty"`
+	// ID and URL are read when the server includes them, which the original
+	// client never relied on. Without URL an item can't be matched to its
+	// video, so duplicates are only found among videos added from this
+	// machine.
+	ID      string `json:"id,omitempty" yaml:"id,omitempty"`
+	Status  string `json:"status" yaml:"status"`
+	Title   string `json:"title,omitempty" yaml:"title,omitempty"`
+	URL     string `json:"url,omitempty" yaml:"url,omitempty"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+	Created string `json:"created,omitempty" yaml:"created,omitempty"`
+
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Published is the episode's date in the feed, which orders the feed.
+	// Empty means the feed uses Created.
+	Published string `json:"published,omitempty" yaml:"published,omitempty"`
+	// Hidden items are left out of the feed but kept in the podcast.
+	Hidden bool `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+
+	// Extra holds any fields the API returned that Item doesn't know about,
+	// so they can still be shown to the user.
+	Extra map[string]any `json:"-" yaml:"-"`
+}
+
+// itemFields are the JSON fields decoded into Item's own fields.
+var itemFields = []string{"id", "status", "title", "url", "error", "created", "description", "published", "hidden"}
+
+func (i *Item) UnmarshalJSON(data []byte) error {
+	type plain Item
+	if err := json.Unmarshal(data, (*plain)(i)); err != nil {
+		return err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for _, name := range itemFields {
+		delete(fields, name)
+	}
+	i.Extra = nil
+	if len(fields) > 0 {
+		i.Extra = fields
+	}
+	return nil
+}
+
+// CreatedAt parses the Created timestamp returned by the API. It returns the
+// zero time when the field is empty or in an unrecognized layout.
+func (i Item) CreatedAt() time.Time {
+	return parseTimestamp(i.Created)
+}
+
+// PublishedAt is the date of the episode in the feed: Published, or
+// CreatedAt when it isn't set.
+func (i Item) PublishedAt() time.Time {
+	if t := parseTimestamp(i.Published); !t.IsZero() {
+		return t
+	}
+	return i.CreatedAt()
+}
+
+func parseTimestamp(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+
+	layouts := []string{
+		time.RFC3339Nano,
+		time.RFC3339,
+		"2006-01-02 15:04:05.999Z",
+		"2006-01-02 15:04:05Z",
+		"2006-01-02 15:04:05.999Z07:00",
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// dateLayouts are the forms of a date typed by the user, besides RFC 3339.
+var dateLayouts = []string{"2006-01-02 15:04", "2006-01-02"}
+
+// ParseDate parses a date typed by the user: RFC 3339, "2006-01-02 15:04"
+// or "2006-01-02", the latter two in local time.
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD or YYYY-MM-DD HH:MM", s)
+}
+
+// UpdateItemRequestBody changes the fields that are set and leaves the nil
+// ones as they are.
+type UpdateItemRequestBody struct {
+	PodcastID   string  `json:"podcast_id" yaml:"podcast_id"`
+	ItemID      string  `json:"item_id" yaml:"item_id"`
+	Title       *string `json:"title,omitempty" yaml:"title,omitempty"`
+	Description *string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Published is an RFC 3339 timestamp, or "" to go back to the created
+	// date.
+	Published *string `json:"published,omitempty" yaml:"published,omitempty"`
+	Hidden    *bool   `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+}
+
+type DeleteItemRequestBody struct {
+	PodcastID string `json:"podcast_id" yaml:"podcast_id"`
+	ItemID    string `json:"item_id" yaml:"item_id"`
+}
+
+// Service is the ytrss.xyz API as used by the CLI and TUI. *APIClient
+// implements it over HTTP; package fake provides an in-memory version for
+// tests and demos.
+//
+// ytrss.xyz doesn't document its API. The calls that create, update and
+// delete podcasts and items follow the path scheme and JSON bodies of
+// /podcasts/add-url and are tested only against api/fake and
+// api/fakeserver. A server without one of them answers 404.
+type Service interface {
+	ListPodcasts(ctx context.Context) ([]Podcast, error)
+	CreatePodcast(ctx context.Context, body CreatePodcastRequestBody) (Podcast, error)
+	UpdatePodcast(ctx context.Context, body UpdatePodcastRequestBody) (Podcast, error)
+	DeletePodcast(ctx context.Context, podcastID string) error
+	AddUrlToPodcast(ctx context.Context, podcastID, url string) (Item, error)
+	GetPodcastItems(ctx context.Context, podcastID string) ([]Item, error)
+	UpdateItem(ctx context.Context, body UpdateItemRequestBody) (Item, error)
+	DeleteItem(ctx context.Context, podcastID, itemID string) error
+	GetUsage(ctx context.Context) (*UsageResponse, error)
+
+	GetApiKey() (string, error)
+	SetApiKey(apiKey string) error
+	ClearApiKey() error
+}
+
+var _ Service = (*APIClient)(nil)
+
+// DefaultClient returns the client used by the package-level functions.
+func DefaultClient() *APIClient {
+	return apiClient
+}
+
+// Credentials returns the credential chain used by the default client.
+func Credentials() *credentials.Chain {
+	return apiClient.credentials
 }
 
 func GetApiKey() (string, error) {
-	return keyring.Get(serviceName, "api_key")
+	return apiClient.GetApiKey()
 }
 
 func SetApiKey(apiKey string) error {
-	return keyring.Set(serviceName, "api_key", apiKey)
+	return apiClient.SetApiKey(apiKey)
 }
 
 func ClearApiKey() error {
-	return keyring.Delete(serviceName, "api_key")
+	return apiClient.ClearApiKey()
+}
+
+func ListPodcasts(ctx context.Context) ([]Podcast, error) {
+	return apiClient.ListPodcasts(ctx)
 }
 
-func ListPodcasts() ([]Podcast, error) {
+func CreatePodcast(ctx context.Context, body CreatePodcastRequestBody) (Podcast, error) {
+	return apiClient.CreatePodcast(ctx, body)
+}
+
+func UpdatePodcast(ctx context.Context, body UpdatePodcastRequestBody) (Podcast, error) {
+	return apiClient.UpdatePodcast(ctx, body)
+}
+
+func DeletePodcast(ctx context.Context, podcastID string) error {
+	return apiClient.DeletePodcast(ctx, podcastID)
+}
+
+func AddUrlToPodcast(ctx context.Context, podcastID, url string) (Item, error) {
+	return apiClient.AddUrlToPodcast(ctx, podcastID, url)
+}
+
+func GetPodcastItems(ctx context.Context, podcastID string) ([]Item, error) {
+	return apiClient.GetPodcastItems(ctx, podcastID)
+}
+
+func UpdateItem(ctx context.Context, body UpdateItemRequestBody) (Item, error) {
+	return apiClient.UpdateItem(ctx, body)
+}
+
+func DeleteItem(ctx context.Context, podcastID, itemID string) error {
+	return apiClient.DeleteItem(ctx, podcastID, itemID)
+}
+
+func GetUsage(ctx context.Context) (*UsageResponse, error) {
+	return apiClient.GetUsage(ctx)
+}
+
+func (c *APIClient) GetApiKey() (string, error) {
+	apiKey, _, err := c.credentials.Get()
+	return apiKey, err
+}
+
+func (c *APIClient) SetApiKey(apiKey string) error {
+	_, err := c.credentials.Set(apiKey)
+	return err
+}
+
+func (c *APIClient) ClearApiKey() error {
+	return c.credentials.Clear()
+}
+
+func (c *APIClient) ListPodcasts(ctx context.Context) ([]Podcast, error) {
 	var podcasts []Podcast
-	err := apiClient.do("GET", "/list-podcasts", nil, &podcasts)
+	err := c.do(ctx, "GET", "/list-podcasts", nil, &podcasts)
 	if err != nil {
 		return nil, err
 	}
 	return podcasts, nil
 }
 
-func AddUrlToPodcast(podcastID, url string) (Item, error) {
+// CreatePodcast creates an empty podcast.
+func (c *APIClient) CreatePodcast(ctx context.Context, body CreatePodcastRequestBody) (Podcast, error) {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return Podcast{}, err
+	}
+
+	var podcast Podcast
+	err = c.doWithIdempotencyKey(ctx, "POST", "/podcasts/create", bytes.NewBuffer(jsonBody), newIdempotencyKey(), &podcast)
+	if err != nil {
+		return Podcast{}, err
+	}
+	return podcast, nil
+}
+
+func (c *APIClient) UpdatePodcast(ctx context.Context, body UpdatePodcastRequestBody) (Podcast, error) {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return Podcast{}, err
+	}
+
+	var podcast Podcast
+	err = c.doWithIdempotencyKey(ctx, "POST", "/podcasts/update", bytes.NewBuffer(jsonBody), newIdempotencyKey(), &podcast)
+	if err != nil {
+		return Podcast{}, err
+	}
+	return podcast, nil
+}
+
+// DeletePodcast deletes a podcast and all of its episodes.
+func (c *APIClient) DeletePodcast(ctx context.Context, podcastID string) error {
+	jsonBody, err := json.Marshal(DeletePodcastRequestBody{PodcastID: podcastID})
+	if err != nil {
+		return err
+	}
+	return c.doWithIdempotencyKey(ctx, "POST", "/podcasts/delete", bytes.NewBuffer(jsonBody), newIdempotencyKey(), nil)
+}
+
+func (c *APIClient) AddUrlToPodcast(ctx context.Context, podcastID, url string) (Item, error) {
 	requestBody := AddUrlRequestBody{
 		PodcastID: podcastID,
 		URL:       url,
@@ -76,7 +365,7 @@ func AddUrlToPodcast(podcastID, url string) (Item, error) {
 	}
 
 	var item Item
-	err = apiClient.do("POST", "/podcasts/add-url", bytes.NewBuffer(jsonBody), &item)
+	err = c.doWithIdempotencyKey(ctx, "POST", "/podcasts/add-url", bytes.NewBuffer(jsonBody), newIdempotencyKey(), &item)
 	if err != nil {
 		return Item{}, err
 	}
@@ -84,18 +373,47 @@ func AddUrlToPodcast(podcastID, url string) (Item, error) {
 	return item, nil
 }
 
-func GetPodcastItems(podcastID string) ([]Item, error) {
+func (c *APIClient) GetPodcastItems(ctx context.Context, podcastID string) ([]Item, error) {
 	var items []Item
-	err := apiClient.do("GET", "/get-items/"+podcastID, nil, &items)
+	err := c.do(ctx, "GET", "/get-items/"+podcastID, nil, &items)
 	if err != nil {
 		return nil, err
 	}
 	return items, nil
 }
 
-func GetUsage() (*UsageResponse, error) {
+// UpdateItem changes the fields of an episode that are set in body.
+func (c *APIClient) UpdateItem(ctx context.Context, body UpdateItemRequestBody) (Item, error) {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return Item{}, err
+	}
+
+	var item Item
+	err = c.doWithIdempotencyKey(ctx, "POST", "/items/update", bytes.NewBuffer(jsonBody), newIdempotencyKey(), &item)
+	if err != nil {
+		return Item{}, err
+	}
+	// A server that doesn't know hidden would ignore it and leave the
+	// episode in the feed.
+	if body.Hidden != nil && item.Hidden != *body.Hidden {
+		return Item{}, errors.New("the server did not change whether the episode is hidden, it may not support hiding episodes")
+	}
+	return item, nil
+}
+
+// DeleteItem deletes an episode and its audio, freeing the storage it used.
+func (c *APIClient) DeleteItem(ctx context.Context, podcastID, itemID string) error {
+	jsonBody, err := json.Marshal(DeleteItemRequestBody{PodcastID: podcastID, ItemID: itemID})
+	if err != nil {
+		return err
+	}
+	return c.doWithIdempotencyKey(ctx, "POST", "/items/delete", bytes.NewBuffer(jsonBody), newIdempotencyKey(), nil)
+}
+
+func (c *APIClient) GetUsage(ctx context.Context) (*UsageResponse, error) {
 	var usageResponse UsageResponse
-	err := apiClient.do("GET", "/get-usage", nil, &usageResponse)
+	err := c.do(ctx, "GET", "/get-usage", nil, &usageResponse)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ErrInsecureBaseURL is returned when the API key would be sent over plain
+// HTTP to a host other than localhost.
+var ErrInsecureBaseURL = errors.New("refusing to send the API key over plain HTTP")
+
+// ValidateBaseURL checks that rawURL is an absolute http(s) URL. Plain HTTP
+// is only accepted for loopback hosts unless allowInsecureHTTP is set, so a
+// typo in a self-hosted URL cannot leak the API key.
+func ValidateBaseURL(rawURL string, allowInsecureHTTP bool) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL %q: %w", rawURL, err)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid base URL %q: missing host", rawURL)
+	}
+
+	switch u.Scheme {
+	case "https":
+		return nil
+	case "http":
+		if allowInsecureHTTP || isLoopbackHost(u.Hostname()) {
+			return nil
+		}
+		return fmt.Errorf("%w to %s; use https or explicitly allow insecure HTTP", ErrInsecureBaseURL, u.Host)
+	default:
+		return fmt.Errorf("invalid base URL %q: scheme must be http or https", rawURL)
+	}
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
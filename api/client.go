@@ -1,59 +1,317 @@
 package api
 
 import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	mathrand "math/rand/v2"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/credentials"
+)
+
+const (
+	DefaultConnectTimeout = 10 * time.Second
+	DefaultTimeout        = 30 * time.Second
 )
 
+// RetryPolicy controls how transient failures are retried. Only network
+// errors and 429, 502, 503 and 504 responses are retried, and only for
+// idempotent methods unless RetryPOST is set.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry; it doubles on every
+	// subsequent attempt up to MaxDelay, with jitter applied.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// RetryPOST allows retrying POST requests that carry an idempotency key,
+	// such as AddUrlToPodcast. It is opt-in because a retried POST can create
+	// a duplicate episode if the server ignores the key.
+	RetryPOST bool
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
 type APIClient struct {
-	client  *http.Client
-	baseURL string
+	client            *http.Client
+	baseURL           string
+	retry             RetryPolicy
+	credentials       *credentials.Chain
+	allowInsecureHTTP bool
+
+	// sleep waits between attempts and jitter picks a random duration in
+	// [0, n). Tests replace both to check the backoff without waiting.
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func(n time.Duration) time.Duration
 }
 
-func NewAPIClient(baseURL string) *APIClient {
-	return &APIClient{
-		client:  &http.Client{},
-		baseURL: baseURL,
+type Option func(*APIClient)
+
+// WithTimeout bounds the total time of a single attempt, including reading
+// the response body. Zero disables the limit.
+func WithTimeout(d time.Duration) Option {
+	return func(c *APIClient) {
+		c.client.Timeout = d
 	}
 }
 
-func (c *APIClient) do(method, path string, body io.Reader, v any) error {
-	apiKey, err := GetApiKey()
-	if err != nil {
-		return fmt.Errorf("API key not set. Please set an API key")
+// WithConnectTimeout bounds establishing the TCP connection and the TLS
+// handshake. Zero disables the limit.
+func WithConnectTimeout(d time.Duration) Option {
+	return func(c *APIClient) {
+		c.client.Transport = newTransport(d)
+	}
+}
+
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *APIClient) {
+		c.retry = p
+	}
+}
+
+// WithCredentials sets where the client looks up the API key.
+func WithCredentials(chain *credentials.Chain) Option {
+	return func(c *APIClient) {
+		c.credentials = chain
+	}
+}
+
+// WithAllowInsecureHTTP permits sending the API key over plain HTTP to hosts
+// other than localhost. Only use it for servers on a trusted network.
+func WithAllowInsecureHTTP(allow bool) Option {
+	return func(c *APIClient) {
+		c.allowInsecureHTTP = allow
+	}
+}
+
+// BaseURL returns the API endpoint the client sends requests to.
+func (c *APIClient) BaseURL() string {
+	return c.baseURL
+}
+
+func NewAPIClient(baseURL string, opts ...Option) *APIClient {
+	c := &APIClient{
+		client: &http.Client{
+			Timeout:   DefaultTimeout,
+			Transport: newTransport(DefaultConnectTimeout),
+		},
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		retry:       DefaultRetryPolicy,
+		credentials: credentials.Default(credentials.Options{}),
+		sleep:       sleep,
+		jitter:      mathrand.N[time.Duration],
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func newTransport(connectTimeout time.Duration) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   connectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+	return transport
+}
+
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (c *APIClient) do(ctx context.Context, method, path string, body io.Reader, v any) error {
+	return c.doWithIdempotencyKey(ctx, method, path, body, "", v)
+}
 
-	req, err := http.NewRequest(method, c.baseURL+path, body)
+// doWithIdempotencyKey sends the request, retrying transient failures
+// according to the client's RetryPolicy. A non-empty idempotencyKey is sent
+// in the Idempotency-Key header and makes POST requests eligible for retry.
+func (c *APIClient) doWithIdempotencyKey(ctx context.Context, method, path string, body io.Reader, idempotencyKey string, v any) error {
+	if err := ValidateBaseURL(c.baseURL, c.allowInsecureHTTP); err != nil {
+		return &Error{Method: method, Path: path, Err: err}
+	}
+
+	apiKey, _, err := c.credentials.Get()
 	if err != nil {
+		return &Error{Method: method, Path: path, Err: fmt.Errorf("%w: %w", ErrNoAPIKey, err)}
+	}
+
+	var payload []byte
+	if body != nil {
+		payload, err = io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	maxAttempts := 1
+	if c.retryable(method, idempotencyKey) {
+		maxAttempts = max(c.retry.MaxAttempts, 1)
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+		req.Header.Set("Content-Type", "application/json")
+		if idempotencyKey != "" {
+			req.Header.Set("Idempotency-Key", idempotencyKey)
+		}
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if attempt < maxAttempts {
+				if delay, ok := c.retryDelay(attempt, nil); ok {
+					if err := c.sleep(ctx, delay); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+			if c.baseURL == DefaultBaseURL && refusesHTTPS(err) {
+				err = fmt.Errorf("%w: %w", err, ErrDefaultHTTPS)
+			}
+			return &Error{Method: method, Path: path, Attempts: attempt, Err: err}
+		}
+
+		if retryableStatus(resp.StatusCode) && attempt < maxAttempts {
+			if delay, ok := c.retryDelay(attempt, resp); ok {
+				resp.Body.Close()
+				if err := c.sleep(ctx, delay); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		err = decodeResponse(resp, v)
+		if apiErr, ok := err.(*Error); ok {
+			apiErr.Method = method
+			apiErr.Path = path
+			apiErr.Attempts = attempt
+		}
 		return err
 	}
+}
 
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Content-Type", "application/json")
+// decodeResponse closes the body and either decodes it into v or turns it
+// into an *Error. The caller fills in the request details.
+func decodeResponse(resp *http.Response, v any) error {
+	defer resp.Body.Close()
 
-	resp, err := c.client.Do(req)
+	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("could not connect to the API")
+		return &Error{StatusCode: resp.StatusCode, Err: fmt.Errorf("failed to read response body: %w", err)}
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed: %s - %s", resp.Status, string(bodyBytes))
+		code, message := parseErrorBody(bodyBytes)
+		return &Error{StatusCode: resp.StatusCode, Code: code, Message: message}
 	}
 
 	if v != nil {
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to read response body: %w", err)
-		}
-
 		if err := json.Unmarshal(bodyBytes, v); err != nil {
-			return fmt.Errorf("failed to decode JSON response (status %d): %w\nResponse body: %s", resp.StatusCode, err, string(bodyBytes))
+			return &Error{StatusCode: resp.StatusCode, Err: fmt.Errorf("failed to decode JSON response: %w", err)}
 		}
 	}
 
 	return nil
 }
+
+func (c *APIClient) retryable(method, idempotencyKey string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return c.retry.RetryPOST && idempotencyKey != ""
+	}
+	return false
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay returns how long to wait before the next attempt. It honors
+// the server's Retry-After header and reports false when the server asks
+// for a longer pause than MaxDelay.
+func (c *APIClient) retryDelay(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if after, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return after, after <= c.retry.MaxDelay
+		}
+	}
+	return c.backoff(attempt), true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// backoff returns the delay before retry number attempt using exponential
+// backoff with equal jitter: half of the delay is fixed and half random.
+func (c *APIClient) backoff(attempt int) time.Duration {
+	delay := c.retry.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > c.retry.MaxDelay {
+		delay = c.retry.MaxDelay
+	}
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + c.jitter(half)
+}
+
+// parseRetryAfter accepts both forms allowed by RFC 9110: a number of
+// seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
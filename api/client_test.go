@@ -0,0 +1,286 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/credentials"
+)
+
+type staticKey string
+
+func (k staticKey) Name() string         { return "test key" }
+func (k staticKey) Get() (string, error) { return string(k), nil }
+
+// retryServer answers each request with the next status in statuses and
+// 200 once they run out. It records the requests it received.
+type retryServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	header   http.Header
+	requests []*http.Request
+}
+
+func newRetryServer(t *testing.T, statuses ...int) *retryServer {
+	s := &retryServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, r)
+		if len(s.statuses) == 0 {
+			if r.Method == http.MethodGet {
+				w.Write([]byte(`[]`))
+			} else {
+				w.Write([]byte(`{}`))
+			}
+			return
+		}
+		status := s.statuses[0]
+		s.statuses = s.statuses[1:]
+		for k, v := range s.header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(`{"message": "try again"}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *retryServer) attempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+// newTestClient returns a client for srv whose sleeps are recorded instead
+// of waited for and whose jitter is always zero.
+func newTestClient(srv *retryServer, policy RetryPolicy) (*APIClient, *[]time.Duration) {
+	c := NewAPIClient(srv.URL,
+		WithRetryPolicy(policy),
+		WithCredentials(&credentials.Chain{Sources: []credentials.Source{staticKey("test-key")}}),
+	)
+	var sleeps []time.Duration
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return ctx.Err()
+	}
+	c.jitter = func(time.Duration) time.Duration { return 0 }
+	return c, &sleeps
+}
+
+var testPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    time.Second,
+}
+
+func TestRetryBackoff(t *testing.T) {
+	srv := newRetryServer(t, 503, 502, 504)
+	c, sleeps := newTestClient(srv, testPolicy)
+
+	if _, err := c.ListPodcasts(context.Background()); err != nil {
+		t.Fatalf("ListPodcasts failed: %v", err)
+	}
+	if got := srv.attempts(); got != 4 {
+		t.Errorf("server saw %d attempts, want 4", got)
+	}
+	// Equal jitter keeps half of each doubling delay fixed.
+	want := []time.Duration{50 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond}
+	if !reflect.DeepEqual(*sleeps, want) {
+		t.Errorf("sleeps = %v, want %v", *sleeps, want)
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	c := NewAPIClient("https://example.com", WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   400 * time.Millisecond,
+		MaxDelay:    time.Second,
+	}))
+	var spans []time.Duration
+	c.jitter = func(n time.Duration) time.Duration {
+		spans = append(spans, n)
+		return n - 1
+	}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 400*time.Millisecond - 1},
+		{2, 800*time.Millisecond - 1},
+		{3, time.Second - 1},
+		{30, time.Second - 1},
+	}
+	for _, tt := range tests {
+		if got := c.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+	want := []time.Duration{200 * time.Millisecond, 400 * time.Millisecond, 500 * time.Millisecond, 500 * time.Millisecond}
+	if !reflect.DeepEqual(spans, want) {
+		t.Errorf("jitter spans = %v, want %v", spans, want)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	srv := newRetryServer(t, 429)
+	srv.header = http.Header{"Retry-After": {"1"}}
+	c, sleeps := newTestClient(srv, testPolicy)
+
+	if _, err := c.ListPodcasts(context.Background()); err != nil {
+		t.Fatalf("ListPodcasts failed: %v", err)
+	}
+	if want := []time.Duration{time.Second}; !reflect.DeepEqual(*sleeps, want) {
+		t.Errorf("sleeps = %v, want %v", *sleeps, want)
+	}
+}
+
+func TestRetryAfterBeyondMaxDelay(t *testing.T) {
+	srv := newRetryServer(t, 503)
+	srv.header = http.Header{"Retry-After": {"120"}}
+	c, sleeps := newTestClient(srv, testPolicy)
+
+	_, err := c.ListPodcasts(context.Background())
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 503 || apiErr.Attempts != 1 {
+		t.Fatalf("ListPodcasts error = %v, want a 503 after 1 attempt", err)
+	}
+	if len(*sleeps) != 0 {
+		t.Errorf("slept %v, want no retry", *sleeps)
+	}
+}
+
+func TestRetryStopsAtMaxAttempts(t *testing.T) {
+	srv := newRetryServer(t, 503, 503, 503, 503, 503, 503)
+	c, sleeps := newTestClient(srv, testPolicy)
+
+	_, err := c.ListPodcasts(context.Background())
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 503 {
+		t.Fatalf("ListPodcasts error = %v, want a 503", err)
+	}
+	if apiErr.Attempts != 4 {
+		t.Errorf("Attempts = %d, want 4", apiErr.Attempts)
+	}
+	if got := srv.attempts(); got != 4 {
+		t.Errorf("server saw %d attempts, want 4", got)
+	}
+	if len(*sleeps) != 3 {
+		t.Errorf("slept %d times, want 3", len(*sleeps))
+	}
+}
+
+func TestNoRetryOnClientErrors(t *testing.T) {
+	for _, status := range []int{400, 401, 403, 404, 409, 422} {
+		srv := newRetryServer(t, status)
+		c, sleeps := newTestClient(srv, testPolicy)
+
+		_, err := c.ListPodcasts(context.Background())
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != status {
+			t.Errorf("%d: ListPodcasts error = %v", status, err)
+			continue
+		}
+		if got := srv.attempts(); got != 1 || len(*sleeps) != 0 {
+			t.Errorf("%d: %d attempts and %d sleeps, want 1 and 0", status, got, len(*sleeps))
+		}
+	}
+}
+
+func TestRetryPOSTReusesIdempotencyKey(t *testing.T) {
+	srv := newRetryServer(t, 502, 503)
+	policy := testPolicy
+	policy.RetryPOST = true
+	c, _ := newTestClient(srv, policy)
+
+	if _, err := c.AddUrlToPodcast(context.Background(), "pod001", "https://www.youtube.com/watch?v=dQw4w9WgXcQ"); err != nil {
+		t.Fatalf("AddUrlToPodcast failed: %v", err)
+	}
+	if len(srv.requests) != 3 {
+		t.Fatalf("server saw %d attempts, want 3", len(srv.requests))
+	}
+	key := srv.requests[0].Header.Get("Idempotency-Key")
+	if key == "" {
+		t.Fatal("first attempt has no Idempotency-Key")
+	}
+	for i, r := range srv.requests[1:] {
+		if got := r.Header.Get("Idempotency-Key"); got != key {
+			t.Errorf("attempt %d Idempotency-Key = %q, want %q", i+2, got, key)
+		}
+	}
+}
+
+func TestNoRetryPOSTByDefault(t *testing.T) {
+	srv := newRetryServer(t, 503)
+	c, sleeps := newTestClient(srv, testPolicy)
+
+	if _, err := c.AddUrlToPodcast(context.Background(), "pod001", "https://www.youtube.com/watch?v=dQw4w9WgXcQ"); err == nil {
+		t.Fatal("AddUrlToPodcast succeeded, want the 503")
+	}
+	if got := srv.attempts(); got != 1 || len(*sleeps) != 0 {
+		t.Errorf("%d attempts and %d sleeps, want 1 and 0", got, len(*sleeps))
+	}
+}
+
+func TestUpdateItemChecksHidden(t *testing.T) {
+	// The server answers with an item that has no hidden field.
+	c, _ := newTestClient(newRetryServer(t), testPolicy)
+	hidden, visible, title := true, false, "Keynote"
+
+	if _, err := c.UpdateItem(context.Background(), UpdateItemRequestBody{Hidden: &hidden}); err == nil {
+		t.Error("UpdateItem reported a hide the server ignored as done")
+	}
+	if _, err := c.UpdateItem(context.Background(), UpdateItemRequestBody{Hidden: &visible}); err != nil {
+		t.Errorf("UpdateItem(unhide) failed: %v", err)
+	}
+	if _, err := c.UpdateItem(context.Background(), UpdateItemRequestBody{Title: &title}); err != nil {
+		t.Errorf("UpdateItem(title) failed: %v", err)
+	}
+}
+
+type failingTransport struct{ err error }
+
+func (t failingTransport) RoundTrip(*http.Request) (*http.Response, error) { return nil, t.err }
+
+func TestDefaultHTTPSHint(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	noHost := &net.DNSError{Err: "no such host", Name: "ytrss.xyz", IsNotFound: true}
+
+	tests := []struct {
+		name    string
+		baseURL string
+		err     error
+		want    bool
+	}{
+		{"refused", DefaultBaseURL, refused, true},
+		{"plain http answer", DefaultBaseURL, tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}, true},
+		{"no such host", DefaultBaseURL, noHost, false},
+		{"other server", "https://ytrss.example.com/api/v1", refused, false},
+	}
+	for _, tt := range tests {
+		c := NewAPIClient(tt.baseURL,
+			WithRetryPolicy(RetryPolicy{}),
+			WithCredentials(&credentials.Chain{Sources: []credentials.Source{staticKey("test-key")}}),
+		)
+		c.client.Transport = failingTransport{tt.err}
+
+		_, err := c.ListPodcasts(context.Background())
+		if got := errors.Is(err, ErrDefaultHTTPS); got != tt.want {
+			t.Errorf("%s: error %v mentions HTTPS: %v, want %v", tt.name, err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"syscall"
+)
+
+// ErrNoAPIKey is returned when no API key has been configured.
+var ErrNoAPIKey = errors.New("API key not set. Please set an API key")
+
+// ErrDefaultHTTPS is wrapped around connection errors from DefaultBaseURL
+// that suggest the server doesn't serve HTTPS. Earlier releases used
+// InsecureDefaultBaseURL.
+var ErrDefaultHTTPS = errors.New("ytrss now connects to the public server over HTTPS, which it may not serve")
+
+// Error describes a failed API request. StatusCode is zero when no response
+// was received, in which case Err holds the network error.
+type Error struct {
+	Method     string
+	Path       string
+	StatusCode int
+	// Code and Message are taken from the JSON error body when the server
+	// sends one. Message falls back to the raw body.
+	Code     string
+	Message  string
+	Attempts int
+	Err      error
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s: ", e.Method, e.Path)
+
+	switch {
+	case e.StatusCode >= 200 && e.StatusCode < 300 && e.Err != nil:
+		b.WriteString(e.Err.Error())
+	case e.StatusCode != 0:
+		fmt.Fprintf(&b, "API request failed: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+		if e.Message != "" {
+			b.WriteString(": " + e.Message)
+		}
+		if e.Err != nil {
+			b.WriteString(": " + e.Err.Error())
+		}
+	case errors.Is(e.Err, ErrNoAPIKey):
+		b.WriteString(e.Err.Error())
+	default:
+		b.WriteString("could not connect to the API")
+		if e.Err != nil {
+			b.WriteString(": " + e.Err.Error())
+		}
+	}
+
+	if e.Attempts > 1 {
+		fmt.Fprintf(&b, " (after %d attempts)", e.Attempts)
+	}
+	return b.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// refusesHTTPS reports whether a connection error means the server refused
+// the connection or didn't complete a TLS handshake.
+func refusesHTTPS(err error) bool {
+	var recordErr tls.RecordHeaderError
+	var certErr *tls.CertificateVerificationError
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.As(err, &recordErr) || errors.As(err, &certErr)
+}
+
+// IsUnauthorized reports whether err was caused by a missing or rejected
+// API key.
+func IsUnauthorized(err error) bool {
+	if errors.Is(err, ErrNoAPIKey) {
+		return true
+	}
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized
+}
+
+// IsNotFound reports whether the requested resource does not exist.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsQuotaExceeded reports whether the request was refused because the
+// account's storage or usage limit has been reached. A 403 or 400 only
+// counts when its code or message mentions the quota, such as the
+// "quota_exceeded" code; 429 is rate limiting and is retried instead.
+func IsQuotaExceeded(err error) bool {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusPaymentRequired, http.StatusRequestEntityTooLarge:
+		return true
+	case http.StatusForbidden, http.StatusBadRequest:
+		return strings.Contains(strings.ToLower(apiErr.Code+" "+apiErr.Message), "quota")
+	}
+	return false
+}
+
+// parseErrorBody extracts the error code and message from a response body.
+// Both the {"code": ..., "message": ...} and {"error": ...} shapes are
+// understood; anything else is returned verbatim as the message.
+func parseErrorBody(body []byte) (code, message string) {
+	var payload struct {
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+		Error   string          `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", truncate(strings.TrimSpace(string(body)), 200)
+	}
+
+	if len(payload.Code) > 0 {
+		var s string
+		if json.Unmarshal(payload.Code, &s) == nil {
+			code = s
+		} else {
+			var n json.Number
+			if json.Unmarshal(payload.Code, &n) == nil {
+				code = n.String()
+			}
+		}
+	}
+
+	message = payload.Message
+	if message == "" {
+		message = payload.Error
+	}
+	return code, message
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		err  *Error
+		want string
+	}{
+		{
+			&Error{Method: "GET", Path: "/get-usage", StatusCode: 503, Message: "down for maintenance", Attempts: 4},
+			"GET /get-usage: API request failed: 503 Service Unavailable: down for maintenance (after 4 attempts)",
+		},
+		{
+			&Error{Method: "GET", Path: "/list-podcasts", StatusCode: 404, Attempts: 1},
+			"GET /list-podcasts: API request failed: 404 Not Found",
+		},
+		{
+			&Error{Method: "GET", Path: "/list-podcasts", StatusCode: 200, Err: errors.New("failed to decode JSON response")},
+			"GET /list-podcasts: failed to decode JSON response",
+		},
+		{
+			&Error{Method: "POST", Path: "/podcasts/add-url", Err: errors.New("connection refused"), Attempts: 2},
+			"POST /podcasts/add-url: could not connect to the API: connection refused (after 2 attempts)",
+		},
+		{
+			&Error{Method: "GET", Path: "/get-usage", Err: ErrNoAPIKey},
+			"GET /get-usage: " + ErrNoAPIKey.Error(),
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestErrorPredicates(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		unauthorized bool
+		notFound     bool
+		quota        bool
+	}{
+		{name: "nil", err: nil},
+		{name: "plain error", err: errors.New("quota exceeded")},
+		{name: "no api key", err: &Error{Err: fmt.Errorf("%w: none", ErrNoAPIKey)}, unauthorized: true},
+		{name: "401", err: &Error{StatusCode: 401}, unauthorized: true},
+		{name: "wrapped 401", err: fmt.Errorf("listing: %w", &Error{StatusCode: 401}), unauthorized: true},
+		{name: "404", err: &Error{StatusCode: 404}, notFound: true},
+		{name: "402", err: &Error{StatusCode: 402}, quota: true},
+		{name: "413", err: &Error{StatusCode: 413}, quota: true},
+		{name: "403 quota code", err: &Error{StatusCode: 403, Code: "quota_exceeded"}, quota: true},
+		{name: "400 quota message", err: &Error{StatusCode: 400, Message: "Storage quota reached"}, quota: true},
+		{name: "403 other", err: &Error{StatusCode: 403, Message: "forbidden"}},
+		{name: "400 limit", err: &Error{StatusCode: 400, Message: "title exceeds the length limit"}},
+		{name: "429 quota", err: &Error{StatusCode: 429, Code: "quota_exceeded"}},
+		{name: "500", err: &Error{StatusCode: 500, Message: "quota service down"}},
+	}
+	for _, tt := range tests {
+		if got := IsUnauthorized(tt.err); got != tt.unauthorized {
+			t.Errorf("%s: IsUnauthorized = %v, want %v", tt.name, got, tt.unauthorized)
+		}
+		if got := IsNotFound(tt.err); got != tt.notFound {
+			t.Errorf("%s: IsNotFound = %v, want %v", tt.name, got, tt.notFound)
+		}
+		if got := IsQuotaExceeded(tt.err); got != tt.quota {
+			t.Errorf("%s: IsQuotaExceeded = %v, want %v", tt.name, got, tt.quota)
+		}
+	}
+}
+
+func TestParseErrorBody(t *testing.T) {
+	tests := []struct {
+		body      string
+		code, msg string
+	}{
+		{`{"code": "quota_exceeded", "message": "storage limit reached"}`, "quota_exceeded", "storage limit reached"},
+		{`{"code": 42, "message": "numeric"}`, "42", "numeric"},
+		{`{"error": "bad url"}`, "", "bad url"},
+		{`{"message": "both", "error": "ignored"}`, "", "both"},
+		{"  <html>Bad Gateway</html>\n", "", "<html>Bad Gateway</html>"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		code, msg := parseErrorBody([]byte(tt.body))
+		if code != tt.code || msg != tt.msg {
+			t.Errorf("parseErrorBody(%q) = %q, %q, want %q, %q", tt.body, code, msg, tt.code, tt.msg)
+		}
+	}
+}
@@ -0,0 +1,470 @@
+// Package fake provides an in-memory api.Service for tests and demos.
+//
+// Submitted URLs start in the CREATED state and move to SUCCESS or ERROR
+// once ProcessingDelay has passed, the same way the real service reports
+// them, so polling code can be exercised without a server.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/credentials"
+)
+
+// createdLayout matches the timestamps returned by the real API.
+const createdLayout = "2006-01-02 15:04:05.000Z"
+
+type Service struct {
+	// ProcessingDelay is how long a submitted URL stays CREATED.
+	ProcessingDelay time.Duration
+	// EpisodeSize is added to the usage for every successful episode.
+	EpisodeSize int
+	// FailURL decides the outcome of a submission. It returns the error
+	// message for URLs that should fail, or "" for success. Nil means every
+	// URL succeeds.
+	FailURL func(url string) string
+	// OmitItemIDs leaves the ID out of listed items, which the real API
+	// may do.
+	OmitItemIDs bool
+	// ValidAPIKey, when set, is the only key accepted; any other key is
+	// rejected with 401 Unauthorized.
+	ValidAPIKey string
+	// Now returns the current time; it defaults to time.Now.
+	Now func() time.Time
+
+	mu       sync.Mutex
+	apiKey   string
+	podcasts []api.Podcast
+	items    map[string][]*record
+	usage    api.UsageResponse
+	nextID   int
+}
+
+type record struct {
+	item    api.Item
+	url     string
+	readyAt time.Time
+	failure string
+}
+
+var _ api.Service = (*Service)(nil)
+
+// New returns an empty service with the given API key already set.
+func New(apiKey string) *Service {
+	return &Service{
+		EpisodeSize: 50 * 1024 * 1024,
+		apiKey:      apiKey,
+		items:       make(map[string][]*record),
+		usage:       api.UsageResponse{Limit: 10 * 1024 * 1024 * 1024},
+	}
+}
+
+// NewDemo returns a service with a few podcasts and episodes, suitable for
+// trying out the TUI.
+func NewDemo() *Service {
+	s := New("demo-key")
+	s.ProcessingDelay = 5 * time.Second
+	s.FailURL = func(url string) string {
+		if strings.Contains(url, "fail") {
+			return "video is unavailable"
+		}
+		return ""
+	}
+
+	talks := s.AddPodcast("Conference Talks")
+	s.AddPodcast("Interviews")
+	s.AddPodcast("Lectures")
+
+	now := s.now()
+	s.AddItem(talks.ID, api.Item{Status: api.StatusSuccess, Title: "Simple Made Easy"}, "https://www.youtube.com/watch?v=SxdOUGdseq4", now.Add(-72*time.Hour))
+	s.AddItem(talks.ID, api.Item{Status: api.StatusSuccess, Title: "The Mess We're In"}, "https://www.youtube.com/watch?v=lKXe3HUG2l4", now.Add(-48*time.Hour))
+	s.AddItem(talks.ID, api.Item{Status: api.StatusError, Error: "video is private"}, "https://www.youtube.com/watch?v=private0000", now.Add(-24*time.Hour))
+	return s
+}
+
+// AddPodcast creates a podcast and returns it.
+func (s *Service) AddPodcast(title string) api.Podcast {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := api.Podcast{ID: s.newID("pod"), Title: title}
+	s.podcasts = append(s.podcasts, p)
+	return p
+}
+
+// AddItem stores an item as if it had been created at the given time.
+func (s *Service) AddItem(podcastID string, item api.Item, url string, created time.Time) api.Item {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if item.ID == "" {
+		item.ID = s.newID("item")
+	}
+	item.URL = url
+	item.Created = created.UTC().Format(createdLayout)
+	if item.Status == api.StatusSuccess {
+		s.usage.Usage += s.EpisodeSize
+	}
+	s.items[podcastID] = append(s.items[podcastID], &record{item: item, url: url, readyAt: created})
+	return item
+}
+
+// SetLimit changes the storage limit, keeping the current usage.
+func (s *Service) SetLimit(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage.Limit = limit
+}
+
+// SetUsage overrides the current usage and limit.
+func (s *Service) SetUsage(usage api.UsageResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage = usage
+}
+
+func (s *Service) GetApiKey() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.apiKey == "" {
+		return "", credentials.ErrNotFound
+	}
+	return s.apiKey, nil
+}
+
+func (s *Service) SetApiKey(apiKey string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKey = apiKey
+	return nil
+}
+
+func (s *Service) ClearApiKey() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.apiKey == "" {
+		return credentials.ErrNotFound
+	}
+	s.apiKey = ""
+	return nil
+}
+
+func (s *Service) ListPodcasts(ctx context.Context) ([]api.Podcast, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.check(ctx, "GET", "/list-podcasts"); err != nil {
+		return nil, err
+	}
+	return append([]api.Podcast(nil), s.podcasts...), nil
+}
+
+func (s *Service) CreatePodcast(ctx context.Context, body api.CreatePodcastRequestBody) (api.Podcast, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	const path = "/podcasts/create"
+	if err := s.check(ctx, "POST", path); err != nil {
+		return api.Podcast{}, err
+	}
+	p := api.Podcast{
+		Title:       strings.TrimSpace(body.Title),
+		Description: body.Description,
+		Author:      body.Author,
+		Artwork:     body.Artwork,
+		Category:    body.Category,
+	}
+	if err := s.validate("POST", path, p); err != nil {
+		return api.Podcast{}, err
+	}
+
+	p.ID = s.newID("pod")
+	s.podcasts = append(s.podcasts, p)
+	return p, nil
+}
+
+func (s *Service) UpdatePodcast(ctx context.Context, body api.UpdatePodcastRequestBody) (api.Podcast, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	const path = "/podcasts/update"
+	if err := s.check(ctx, "POST", path); err != nil {
+		return api.Podcast{}, err
+	}
+	i := s.podcastIndex(body.PodcastID)
+	if i < 0 {
+		return api.Podcast{}, s.error("POST", path, http.StatusNotFound, "not_found", "podcast not found")
+	}
+
+	p := s.podcasts[i]
+	set(&p.Title, body.Title)
+	set(&p.Description, body.Description)
+	set(&p.Author, body.Author)
+	set(&p.Artwork, body.Artwork)
+	set(&p.Category, body.Category)
+	p.Title = strings.TrimSpace(p.Title)
+	if err := s.validate("POST", path, p); err != nil {
+		return api.Podcast{}, err
+	}
+
+	s.podcasts[i] = p
+	return p, nil
+}
+
+func (s *Service) DeletePodcast(ctx context.Context, podcastID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	const path = "/podcasts/delete"
+	if err := s.check(ctx, "POST", path); err != nil {
+		return err
+	}
+	i := s.podcastIndex(podcastID)
+	if i < 0 {
+		return s.error("POST", path, http.StatusNotFound, "not_found", "podcast not found")
+	}
+
+	s.podcasts = append(s.podcasts[:i:i], s.podcasts[i+1:]...)
+	delete(s.items, podcastID)
+	return nil
+}
+
+func (s *Service) AddUrlToPodcast(ctx context.Context, podcastID, url string) (api.Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	const path = "/podcasts/add-url"
+	if err := s.check(ctx, "POST", path); err != nil {
+		return api.Item{}, err
+	}
+	if !s.hasPodcast(podcastID) {
+		return api.Item{}, s.error("POST", path, http.StatusNotFound, "not_found", "podcast not found")
+	}
+	if s.usage.Limit > 0 && s.usage.Usage+s.EpisodeSize > s.usage.Limit {
+		return api.Item{}, s.error("POST", path, http.StatusForbidden, "quota_exceeded", "storage limit reached")
+	}
+
+	now := s.now()
+	rec := &record{
+		item: api.Item{
+			ID:      s.newID("item"),
+			Status:  api.StatusCreated,
+			URL:     url,
+			Created: now.UTC().Format(createdLayout),
+		},
+		url:     url,
+		readyAt: now.Add(s.ProcessingDelay),
+	}
+	if s.FailURL != nil {
+		rec.failure = s.FailURL(url)
+	}
+	s.items[podcastID] = append(s.items[podcastID], rec)
+	s.advance()
+	return rec.item, nil
+}
+
+func (s *Service) GetPodcastItems(ctx context.Context, podcastID string) ([]api.Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := "/get-items/" + podcastID
+	if err := s.check(ctx, "GET", path); err != nil {
+		return nil, err
+	}
+	if !s.hasPodcast(podcastID) {
+		return nil, s.error("GET", path, http.StatusNotFound, "not_found", "podcast not found")
+	}
+
+	s.advance()
+	items := make([]api.Item, 0, len(s.items[podcastID]))
+	for _, rec := range s.items[podcastID] {
+		item := rec.item
+		if s.OmitItemIDs {
+			item.ID = ""
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Created > items[j].Created
+	})
+	return items, nil
+}
+
+func (s *Service) UpdateItem(ctx context.Context, body api.UpdateItemRequestBody) (api.Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	const path = "/items/update"
+	if err := s.check(ctx, "POST", path); err != nil {
+		return api.Item{}, err
+	}
+	i, err := s.itemIndex("POST", path, body.PodcastID, body.ItemID)
+	if err != nil {
+		return api.Item{}, err
+	}
+	if body.Title != nil && strings.TrimSpace(*body.Title) == "" {
+		return api.Item{}, s.error("POST", path, http.StatusBadRequest, "invalid_request", "title can't be empty")
+	}
+	if body.Published != nil && *body.Published != "" {
+		if _, err := time.Parse(time.RFC3339, *body.Published); err != nil {
+			return api.Item{}, s.error("POST", path, http.StatusBadRequest, "invalid_request", "published must be an RFC 3339 timestamp")
+		}
+	}
+
+	s.advance()
+	item := &s.items[body.PodcastID][i].item
+	set(&item.Title, body.Title)
+	set(&item.Description, body.Description)
+	set(&item.Published, body.Published)
+	if body.Hidden != nil {
+		item.Hidden = *body.Hidden
+	}
+	return *item, nil
+}
+
+// DeleteItem removes an item and gives back the storage it used.
+func (s *Service) DeleteItem(ctx context.Context, podcastID, itemID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	const path = "/items/delete"
+	if err := s.check(ctx, "POST", path); err != nil {
+		return err
+	}
+	i, err := s.itemIndex("POST", path, podcastID, itemID)
+	if err != nil {
+		return err
+	}
+
+	s.advance()
+	records := s.items[podcastID]
+	if records[i].item.Status == api.StatusSuccess {
+		s.usage.Usage = max(s.usage.Usage-s.EpisodeSize, 0)
+	}
+	s.items[podcastID] = append(records[:i:i], records[i+1:]...)
+	return nil
+}
+
+func (s *Service) GetUsage(ctx context.Context) (*api.UsageResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.check(ctx, "GET", "/get-usage"); err != nil {
+		return nil, err
+	}
+	s.advance()
+	usage := s.usage
+	return &usage, nil
+}
+
+// advance finishes every item whose processing delay has passed.
+func (s *Service) advance() {
+	now := s.now()
+	for _, records := range s.items {
+		for _, rec := range records {
+			if rec.item.Status != api.StatusCreated || now.Before(rec.readyAt) {
+				continue
+			}
+			if rec.failure != "" {
+				rec.item.Status = api.StatusError
+				rec.item.Error = rec.failure
+				continue
+			}
+			rec.item.Status = api.StatusSuccess
+			rec.item.Title = titleFor(rec.url)
+			s.usage.Usage += s.EpisodeSize
+		}
+	}
+}
+
+func (s *Service) check(ctx context.Context, method, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.apiKey == "" {
+		return &api.Error{Method: method, Path: path, Err: fmt.Errorf("%w: %w", api.ErrNoAPIKey, credentials.ErrNotFound)}
+	}
+	if s.ValidAPIKey != "" && s.apiKey != s.ValidAPIKey {
+		return s.error(method, path, http.StatusUnauthorized, "unauthorized", "invalid API key")
+	}
+	return nil
+}
+
+func (s *Service) error(method, path string, status int, code, message string) error {
+	return &api.Error{Method: method, Path: path, StatusCode: status, Code: code, Message: message, Attempts: 1}
+}
+
+func set(field, value *string) {
+	if value != nil {
+		*field = *value
+	}
+}
+
+// validate rejects podcasts the real service wouldn't accept.
+func (s *Service) validate(method, path string, p api.Podcast) error {
+	if p.Title == "" {
+		return s.error(method, path, http.StatusBadRequest, "invalid_request", "title is required")
+	}
+	if p.Artwork != "" {
+		u, err := url.Parse(p.Artwork)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return s.error(method, path, http.StatusBadRequest, "invalid_request", "artwork must be an http or https URL")
+		}
+	}
+	return nil
+}
+
+func (s *Service) hasPodcast(id string) bool {
+	return s.podcastIndex(id) >= 0
+}
+
+func (s *Service) podcastIndex(id string) int {
+	for i, p := range s.podcasts {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Service) itemIndex(method, path, podcastID, itemID string) (int, error) {
+	if !s.hasPodcast(podcastID) {
+		return -1, s.error(method, path, http.StatusNotFound, "not_found", "podcast not found")
+	}
+	for i, rec := range s.items[podcastID] {
+		if rec.item.ID == itemID {
+			return i, nil
+		}
+	}
+	return -1, s.error(method, path, http.StatusNotFound, "not_found", "item not found")
+}
+
+func (s *Service) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s%03d", prefix, s.nextID)
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+// titleFor invents an episode title from the URL's last path segment or
+// query value.
+func titleFor(url string) string {
+	id := url
+	if i := strings.LastIndexAny(id, "/="); i >= 0 {
+		id = id[i+1:]
+	}
+	return "Video " + id
+}
@@ -0,0 +1,241 @@
+// Package fakeserver serves the ytrss.xyz API over HTTP from an in-memory
+// fake.Service, for end-to-end tests of the CLI and TUI and for offline
+// demos.
+package fakeserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/api/fake"
+)
+
+// BasePath is where the API is mounted, matching the public server.
+const BasePath = "/api/v1"
+
+// Handler serves the API endpoints backed by a fake.Service.
+type Handler struct {
+	// Backend holds the podcasts, items and usage.
+	Backend *fake.Service
+	// APIKey is the bearer token every request must carry.
+	APIKey string
+	// Latency is added before every response.
+	Latency time.Duration
+
+	mu          sync.Mutex
+	failures    int
+	failStatus  int
+	idempotency map[string]any
+	mux         *http.ServeMux
+}
+
+// NewHandler returns a handler that accepts apiKey and serves backend.
+func NewHandler(backend *fake.Service, apiKey string) *Handler {
+	// Requests are authenticated here, so the backend only needs some key
+	// for its own check to pass.
+	backend.SetApiKey(apiKey)
+
+	h := &Handler{
+		Backend:     backend,
+		APIKey:      apiKey,
+		idempotency: make(map[string]any),
+		mux:         http.NewServeMux(),
+	}
+	h.mux.HandleFunc("GET "+BasePath+"/list-podcasts", h.listPodcasts)
+	h.mux.HandleFunc("POST "+BasePath+"/podcasts/create", h.createPodcast)
+	h.mux.HandleFunc("POST "+BasePath+"/podcasts/update", h.updatePodcast)
+	h.mux.HandleFunc("POST "+BasePath+"/podcasts/delete", h.deletePodcast)
+	h.mux.HandleFunc("POST "+BasePath+"/podcasts/add-url", h.addURL)
+	h.mux.HandleFunc("GET "+BasePath+"/get-items/{id}", h.getItems)
+	h.mux.HandleFunc("POST "+BasePath+"/items/update", h.updateItem)
+	h.mux.HandleFunc("POST "+BasePath+"/items/delete", h.deleteItem)
+	h.mux.HandleFunc("GET "+BasePath+"/get-usage", h.getUsage)
+	return h
+}
+
+// FailNext makes the next n requests fail with the given status, for
+// exercising retries.
+func (h *Handler) FailNext(n, status int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failures = n
+	h.failStatus = status
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.Latency > 0 {
+		select {
+		case <-time.After(h.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if status, ok := h.injectedFailure(); ok {
+		writeError(w, status, "", http.StatusText(status))
+		return
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" || token != h.APIKey {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "invalid API key")
+		return
+	}
+
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) injectedFailure() (int, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.failures <= 0 {
+		return 0, false
+	}
+	h.failures--
+	return h.failStatus, true
+}
+
+func (h *Handler) listPodcasts(w http.ResponseWriter, r *http.Request) {
+	podcasts, err := h.Backend.ListPodcasts(r.Context())
+	respond(w, podcasts, err)
+}
+
+func (h *Handler) createPodcast(w http.ResponseWriter, r *http.Request) {
+	var body api.CreatePodcastRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	h.once(w, r, func() (any, error) {
+		return h.Backend.CreatePodcast(r.Context(), body)
+	})
+}
+
+func (h *Handler) updatePodcast(w http.ResponseWriter, r *http.Request) {
+	var body api.UpdatePodcastRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.PodcastID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "podcast_id is required")
+		return
+	}
+	h.once(w, r, func() (any, error) {
+		return h.Backend.UpdatePodcast(r.Context(), body)
+	})
+}
+
+func (h *Handler) deletePodcast(w http.ResponseWriter, r *http.Request) {
+	var body api.DeletePodcastRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.PodcastID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "podcast_id is required")
+		return
+	}
+	h.once(w, r, func() (any, error) {
+		return struct{}{}, h.Backend.DeletePodcast(r.Context(), body.PodcastID)
+	})
+}
+
+func (h *Handler) addURL(w http.ResponseWriter, r *http.Request) {
+	var body api.AddUrlRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.PodcastID == "" || body.URL == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "podcast_id and url are required")
+		return
+	}
+	h.once(w, r, func() (any, error) {
+		return h.Backend.AddUrlToPodcast(r.Context(), body.PodcastID, body.URL)
+	})
+}
+
+// once honours the Idempotency-Key header: a repeated key returns the
+// response to the first request instead of running it again.
+func (h *Handler) once(w http.ResponseWriter, r *http.Request, run func() (any, error)) {
+	key := r.Header.Get("Idempotency-Key")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if v, ok := h.idempotency[key]; ok && key != "" {
+		respond(w, v, nil)
+		return
+	}
+
+	v, err := run()
+	if err == nil && key != "" {
+		h.idempotency[key] = v
+	}
+	respond(w, v, err)
+}
+
+func (h *Handler) getItems(w http.ResponseWriter, r *http.Request) {
+	items, err := h.Backend.GetPodcastItems(r.Context(), r.PathValue("id"))
+	respond(w, items, err)
+}
+
+func (h *Handler) updateItem(w http.ResponseWriter, r *http.Request) {
+	var body api.UpdateItemRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.PodcastID == "" || body.ItemID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "podcast_id and item_id are required")
+		return
+	}
+	h.once(w, r, func() (any, error) {
+		return h.Backend.UpdateItem(r.Context(), body)
+	})
+}
+
+func (h *Handler) deleteItem(w http.ResponseWriter, r *http.Request) {
+	var body api.DeleteItemRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.PodcastID == "" || body.ItemID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "podcast_id and item_id are required")
+		return
+	}
+	h.once(w, r, func() (any, error) {
+		return struct{}{}, h.Backend.DeleteItem(r.Context(), body.PodcastID, body.ItemID)
+	})
+}
+
+func (h *Handler) getUsage(w http.ResponseWriter, r *http.Request) {
+	usage, err := h.Backend.GetUsage(r.Context())
+	respond(w, usage, err)
+}
+
+func respond(w http.ResponseWriter, v any, err error) {
+	if err != nil {
+		var apiErr *api.Error
+		if errors.As(err, &apiErr) && apiErr.StatusCode != 0 {
+			writeError(w, apiErr.StatusCode, apiErr.Code, apiErr.Message)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "internal", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]string{"code": code, "message": message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Server is a running fake API.
+type Server struct {
+	*httptest.Server
+	*Handler
+}
+
+// New starts a server on a loopback port. Call Close when done.
+func New(backend *fake.Service, apiKey string) *Server {
+	h := NewHandler(backend, apiKey)
+	return &Server{Server: httptest.NewServer(h), Handler: h}
+}
+
+// BaseURL is the URL to pass to api.NewAPIClient or --base-url.
+func (s *Server) BaseURL() string {
+	return s.URL + BasePath
+}
@@ -0,0 +1,116 @@
+package fakeserver
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/api/fake"
+	"github.com/lsherman98/yt-rss-cli/credentials"
+)
+
+type staticKey string
+
+func (k staticKey) Name() string         { return "test key" }
+func (k staticKey) Get() (string, error) { return string(k), nil }
+
+func newClient(srv *Server, apiKey string, policy api.RetryPolicy) *api.APIClient {
+	return api.NewAPIClient(srv.BaseURL(),
+		api.WithRetryPolicy(policy),
+		api.WithCredentials(&credentials.Chain{Sources: []credentials.Source{staticKey(apiKey)}}),
+	)
+}
+
+func TestServesBackend(t *testing.T) {
+	backend := fake.New("key")
+	podcast := backend.AddPodcast("Talks")
+	srv := New(backend, "key")
+	defer srv.Close()
+	client := newClient(srv, "key", api.RetryPolicy{})
+	ctx := context.Background()
+
+	item, err := client.AddUrlToPodcast(ctx, podcast.ID, "https://www.youtube.com/watch?v=aaaaaaaaaaa")
+	if err != nil {
+		t.Fatalf("AddUrlToPodcast failed: %v", err)
+	}
+	items, err := client.GetPodcastItems(ctx, podcast.ID)
+	if err != nil || len(items) != 1 || items[0].ID != item.ID {
+		t.Errorf("GetPodcastItems() = %+v, %v; want the new item", items, err)
+	}
+
+	_, err = client.GetPodcastItems(ctx, "missing")
+	if !api.IsNotFound(err) {
+		t.Errorf("GetPodcastItems(missing) error = %v, want 404", err)
+	}
+}
+
+func TestRejectsWrongKey(t *testing.T) {
+	srv := New(fake.New("key"), "key")
+	defer srv.Close()
+
+	_, err := newClient(srv, "other", api.RetryPolicy{}).ListPodcasts(context.Background())
+	if !api.IsUnauthorized(err) {
+		t.Errorf("ListPodcasts error = %v, want 401", err)
+	}
+}
+
+func TestFailNextIsRetried(t *testing.T) {
+	srv := New(fake.New("key"), "key")
+	defer srv.Close()
+	client := newClient(srv, "key", api.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	srv.FailNext(2, http.StatusServiceUnavailable)
+	if _, err := client.ListPodcasts(context.Background()); err != nil {
+		t.Errorf("ListPodcasts failed after 2 injected failures and 3 attempts: %v", err)
+	}
+
+	srv.FailNext(3, http.StatusServiceUnavailable)
+	_, err := client.ListPodcasts(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Errorf("ListPodcasts error = %v, want a 503 after 3 attempts", err)
+	}
+}
+
+func TestIdempotencyKeyReplaysResponse(t *testing.T) {
+	backend := fake.New("key")
+	podcast := backend.AddPodcast("Talks")
+	srv := New(backend, "key")
+	defer srv.Close()
+
+	add := func() string {
+		t.Helper()
+		body := `{"podcast_id": "` + podcast.ID + `", "url": "https://www.youtube.com/watch?v=aaaaaaaaaaa"}`
+		req, err := http.NewRequest("POST", srv.BaseURL()+"/podcasts/add-url", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer key")
+		req.Header.Set("Idempotency-Key", "same-key")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("add-url returned %d", resp.StatusCode)
+		}
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	first, second := add(), add()
+	if first != second {
+		t.Errorf("replayed response %s differs from the first %s", second, first)
+	}
+	items, _ := backend.GetPodcastItems(context.Background(), podcast.ID)
+	if len(items) != 1 {
+		t.Errorf("podcast has %d items after a repeated key, want 1", len(items))
+	}
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/history"
+	"github.com/lsherman98/yt-rss-cli/youtube"
+	"github.com/spf13/cobra"
+)
+
+func newAddCmd() *cobra.Command {
+	var (
+		podcastQuery string
+		fromFile     string
+		concurrency  int
+		wait         bool
+		timeout      time.Duration
+		interval     time.Duration
+		force        bool
+		skipExisting bool
+	)
+
+	addCmd := &cobra.Command{
+		Use:   "add --podcast <podcast> [url]...",
+		Short: "Add YouTube videos to a podcast",
+		Long: "Submit one or more YouTube URLs to a podcast. The podcast may be given by ID or title.\n\n" +
+			"URLs can be passed as arguments or read from a file with --from-file, one per\n" +
+			"line (use - for stdin). Blank lines and lines starting with # are ignored.\n\n" +
+			"Every URL must be a YouTube video, playlist or channel link. Video links are\n" +
+			"rewritten to the canonical https://www.youtube.com/watch?v=ID form; playlists\n" +
+			"and channels are expanded into their latest videos (YouTube's feeds list at\n" +
+			"most 15) before anything is submitted.\n\n" +
+			"Videos already in the podcast are reported and nothing is submitted (exit\n" +
+			"code 5) unless --force or --skip-existing is given. Episodes are matched by\n" +
+			"the video URL the server returns for them, and by the local history of\n" +
+			"videos submitted from this machine. If the server doesn't return episode\n" +
+			"URLs, only videos added from this machine are detected.\n\n" +
+			"Exits 1 if any URL could not be submitted. With --wait the command then blocks\n" +
+			"until every episode has finished processing and exits 3 if any episode failed\n" +
+			"and 4 if --timeout expired first.",
+		Example: "  ytrss add --podcast \"My Talks\" https://www.youtube.com/watch?v=dQw4w9WgXcQ\n" +
+			"  ytrss add --podcast abc123 --wait --timeout 10m https://youtu.be/dQw4w9WgXcQ\n" +
+			"  ytrss add --podcast abc123 --from-file urls.txt --concurrency 8\n" +
+			"  ytrss add --podcast abc123 --skip-existing https://www.youtube.com/@channel",
+		Args: usageArgs(cobra.ArbitraryArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if interval <= 0 {
+				return usageError("--interval must be positive")
+			}
+			if concurrency < 1 {
+				return usageError("--concurrency must be at least 1")
+			}
+
+			urls := args
+			if fromFile != "" {
+				fileURLs, err := readURLList(fromFile, cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("reading URLs: %w", err)
+				}
+				urls = append(urls, fileURLs...)
+			}
+			if len(urls) == 0 {
+				return usageError("no URLs given, pass them as arguments or with --from-file")
+			}
+			urls, err := expandURLs(cmd.Context(), urls, youtube.NewFeedClient(), func(line string) {
+				fmt.Fprintln(cmd.ErrOrStderr(), line)
+			})
+			if err != nil {
+				return err
+			}
+
+			podcast, err := resolvePodcast(cmd.Context(), podcastQuery)
+			if err != nil {
+				return err
+			}
+
+			hist, err := history.Load()
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: duplicate checks will only use the server: reading history: %v\n", err)
+				hist = nil
+			}
+			if !force {
+				dups, err := findDuplicates(cmd.Context(), podcast.ID, urls, hist)
+				if err != nil {
+					return err
+				}
+				if len(dups) > 0 && skipExisting {
+					for _, dup := range dups {
+						fmt.Fprintf(cmd.ErrOrStderr(), "Skipping %s: %s\n", dup.URL, dup.Reason)
+					}
+					urls = withoutDuplicates(urls, dups)
+					if len(urls) == 0 {
+						fmt.Fprintln(cmd.ErrOrStderr(), "Nothing new to submit.")
+						return nil
+					}
+				} else if len(dups) > 0 {
+					lines := make([]string, len(dups))
+					for i, dup := range dups {
+						lines[i] = fmt.Sprintf("  %s: %s", dup.URL, dup.Reason)
+					}
+					return &ExitError{Code: ExitDuplicate, Err: fmt.Errorf(
+						"%d URL(s) already added to %s, nothing was submitted (use --force to add them anyway or --skip-existing to add only the new ones):\n%s",
+						len(dups), podcast.Title, strings.Join(lines, "\n"))}
+				}
+			}
+
+			subs := submitAll(cmd.Context(), podcast.ID, urls, concurrency, func(sub *submission) {
+				fmt.Fprintln(cmd.OutOrStdout(), submissionLine(sub, podcast.Title))
+			})
+
+			succeeded, failed := summarizeSubmissions(subs)
+			if hist != nil {
+				for _, sub := range succeeded {
+					if err := hist.Record(podcast.ID, sub.URL, sub.Item.ID, sub.SubmittedAt); err != nil {
+						fmt.Fprintf(cmd.ErrOrStderr(), "Warning: saving history: %v\n", err)
+						break
+					}
+				}
+			}
+			if len(urls) > 1 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Submitted %d of %d URL(s), %d failed\n", len(succeeded), len(urls), failed)
+			}
+
+			if wait && len(succeeded) > 0 {
+				if err := waitAndReport(cmd, podcast.ID, succeeded, timeout, interval); err != nil {
+					return err
+				}
+			}
+
+			if failed > 0 {
+				return &ExitError{Code: ExitFailure, Err: fmt.Errorf("%d of %d URL(s) could not be submitted", failed, len(urls))}
+			}
+			return nil
+		},
+	}
+
+	addCmd.Flags().StringVarP(&podcastQuery, "podcast", "p", "", "podcast ID or title (required)")
+	addCmd.Flags().StringVarP(&fromFile, "from-file", "f", "", "read URLs from a file, one per line (- for stdin)")
+	addCmd.Flags().IntVarP(&concurrency, "concurrency", "c", 4, "maximum number of URLs submitted at once")
+	addCmd.Flags().BoolVarP(&wait, "wait", "w", false, "wait until the episodes finish processing")
+	addCmd.Flags().DurationVar(&timeout, "timeout", 0, "give up waiting after this long, e.g. 10m (0 waits forever)")
+	addCmd.Flags().DurationVar(&interval, "interval", 3*time.Second, "how often to poll while waiting")
+	addCmd.Flags().BoolVar(&force, "force", false, "submit videos even if they were already added")
+	addCmd.Flags().BoolVar(&skipExisting, "skip-existing", false, "leave out videos that were already added instead of failing")
+	addCmd.MarkFlagsMutuallyExclusive("force", "skip-existing")
+	_ = addCmd.MarkFlagRequired("podcast")
+
+	return addCmd
+}
+
+func waitAndReport(cmd *cobra.Command, podcastID string, subs []*submission, timeout, interval time.Duration) error {
+	ctx := cmd.Context()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Waiting for %d episode(s) to finish processing...\n", len(subs))
+	waitErr := waitForItems(ctx, podcastID, subs, interval)
+	if waitErr != nil && !errors.Is(waitErr, context.DeadlineExceeded) {
+		return waitErr
+	}
+
+	failed := 0
+	for _, sub := range subs {
+		switch sub.Item.Status {
+		case api.StatusSuccess:
+			fmt.Fprintf(cmd.OutOrStdout(), "✓ %s: %s\n", sub.URL, sub.Item.Title)
+		case api.StatusError:
+			failed++
+			fmt.Fprintf(cmd.ErrOrStderr(), "❌ %s: %s\n", sub.URL, itemErrorText(sub.Item))
+		default:
+			fmt.Fprintf(cmd.ErrOrStderr(), "⏳ %s: still %s\n", sub.URL, sub.Item.Status)
+		}
+	}
+
+	switch {
+	case failed > 0:
+		return &ExitError{Code: ExitJobFailed, Err: fmt.Errorf("%d of %d episode(s) failed", failed, len(subs))}
+	case waitErr != nil:
+		return &ExitError{Code: ExitTimeout, Err: fmt.Errorf("timed out after %s waiting for episodes to finish", timeout)}
+	}
+	return nil
+}
+
+func itemErrorText(item api.Item) string {
+	if item.Error == "" {
+		return "processing failed"
+	}
+	return item.Error
+}
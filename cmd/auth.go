@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/credentials"
+	"github.com/spf13/cobra"
+)
+
+func newAuthCmd(s *session) *cobra.Command {
+	authCmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage the stored API key",
+		Long: "Manage the API key of the active profile.\n\n" +
+			"The API key is looked up in this order, and the first one found is used:\n" +
+			"  1. the file given with --api-key-file\n" +
+			"  2. the " + credentials.EnvAPIKey + " environment variable\n" +
+			"  3. the OS keyring\n" +
+			"  4. an encrypted file in the ytrss config directory\n\n" +
+			"The encrypted file is protected with " + credentials.EnvPassphrase + " when set, and\n" +
+			"otherwise with a key derived from the machine ID and user name.",
+	}
+
+	authCmd.AddCommand(newAuthLoginCmd(), newAuthLogoutCmd(), newAuthStatusCmd(s))
+	return authCmd
+}
+
+func newAuthLoginCmd() *cobra.Command {
+	var store string
+
+	loginCmd := &cobra.Command{
+		Use:   "login",
+		Short: "Store an API key",
+		Long: "Store an API key in the OS keyring, falling back to the encrypted file when no\n" +
+			"keyring is available.\n\n" +
+			"When stdin is a terminal the key is prompted for without echo; otherwise\n" +
+			"it is read from stdin, e.g. `echo $KEY | ytrss auth login`.",
+		Args: usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain := api.Credentials()
+			target, err := selectStore(chain, store)
+			if err != nil {
+				return err
+			}
+
+			apiKey, err := readAPIKey(cmd)
+			if err != nil {
+				return err
+			}
+			if apiKey == "" {
+				return usageError("no API key provided")
+			}
+
+			var saved credentials.Store
+			if target != nil {
+				err = target.Set(apiKey)
+				saved = target
+			} else {
+				saved, err = chain.Set(apiKey)
+			}
+			if err != nil {
+				return fmt.Errorf("saving API key: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "API key saved to the %s.\n", saved.Name())
+
+			if _, active, err := chain.Get(); err == nil && active.Name() != saved.Name() {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Note: the key from the %s takes precedence.\n", active.Name())
+			}
+			return nil
+		},
+	}
+
+	loginCmd.Flags().StringVar(&store, "store", "auto", "where to save the key: auto, keyring or file")
+	return loginCmd
+}
+
+func newAuthLogoutCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logout",
+		Short: "Remove the stored API key",
+		Long:  "Remove the API key from the OS keyring and the encrypted file.",
+		Args:  usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := api.ClearApiKey()
+			if errors.Is(err, credentials.ErrNotFound) {
+				fmt.Fprintln(cmd.OutOrStdout(), "No stored API key to clear.")
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("clearing API key: %w", err)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "API key cleared successfully!")
+			return nil
+		},
+	}
+}
+
+func newAuthStatusCmd(s *session) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show which API key source is active",
+		Args:  usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain := api.Credentials()
+			out := cmd.OutOrStdout()
+
+			fmt.Fprintf(out, "Profile: %s\n", s.profile)
+
+			apiKey, active, err := chain.Get()
+			if err == nil {
+				fmt.Fprintf(out, "Logged in with %s from the %s\n\n", maskKey(apiKey), active.Name())
+			} else {
+				fmt.Fprintln(out, "Not logged in")
+				fmt.Fprintln(out)
+			}
+
+			fmt.Fprintln(out, "Sources, in order of precedence:")
+			for i, src := range chain.Sources {
+				state := "key found"
+				if _, err := src.Get(); errors.Is(err, credentials.ErrNotFound) {
+					state = "no key"
+				} else if err != nil {
+					state = "unavailable: " + err.Error()
+				}
+				marker := " "
+				if active != nil && src.Name() == active.Name() {
+					marker = "*"
+				}
+				fmt.Fprintf(out, "%s %d. %s (%s)\n", marker, i+1, src.Name(), state)
+			}
+
+			if err != nil {
+				return &ExitError{Code: ExitFailure}
+			}
+			return nil
+		},
+	}
+}
+
+// selectStore returns the store named by --store, or nil for "auto".
+func selectStore(chain *credentials.Chain, name string) (credentials.Store, error) {
+	if name == "auto" {
+		return nil, nil
+	}
+	for _, store := range chain.Stores() {
+		switch store.(type) {
+		case credentials.Keyring:
+			if name == "keyring" {
+				return store, nil
+			}
+		case *credentials.EncryptedFile:
+			if name == "file" {
+				return store, nil
+			}
+		}
+	}
+	return nil, usageError("unknown --store %q (valid: auto, keyring, file)", name)
+}
+
+func maskKey(apiKey string) string {
+	if len(apiKey) <= 8 {
+		return strings.Repeat("*", len(apiKey))
+	}
+	return apiKey[:4] + "…" + apiKey[len(apiKey)-4:]
+}
+
+func readAPIKey(cmd *cobra.Command) (string, error) {
+	in := cmd.InOrStdin()
+	if f, ok := in.(*os.File); ok && term.IsTerminal(f.Fd()) {
+		fmt.Fprint(cmd.ErrOrStderr(), "Paste your API key: ")
+		key, err := term.ReadPassword(f.Fd())
+		fmt.Fprintln(cmd.ErrOrStderr())
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(key)), nil
+	}
+
+	return readLine(in)
+}
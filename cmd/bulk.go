@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/history"
+	"github.com/lsherman98/yt-rss-cli/youtube"
+)
+
+// readURLList reads one URL per line from path, or from stdin when path is
+// "-". Blank lines and lines starting with # are ignored.
+func readURLList(path string, stdin io.Reader) ([]string, error) {
+	r := stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var urls []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return urls, nil
+}
+
+// expandURLs rewrites every video link into its canonical YouTube form so
+// that tracking parameters and alternate hosts don't reach the API, and
+// replaces playlist and channel links with the videos in their feeds. Any
+// input that is neither fails the whole batch with a usage error before
+// any feed is fetched. report is told how each list was expanded.
+func expandURLs(ctx context.Context, urls []string, feeds *youtube.FeedClient, report func(string)) ([]string, error) {
+	type link struct {
+		raw   string
+		video string
+		list  *youtube.List
+	}
+
+	links := make([]link, len(urls))
+	var invalid []string
+	for i, raw := range urls {
+		links[i].raw = raw
+		video, err := youtube.Canonicalize(raw)
+		if err == nil {
+			links[i].video = video
+			continue
+		}
+		if list, listErr := youtube.ParseList(raw); listErr == nil {
+			links[i].list = &list
+			continue
+		}
+		invalid = append(invalid, fmt.Sprintf("  %s: %v", raw, err))
+	}
+	if len(invalid) > 0 {
+		return nil, usageError("%d invalid URL(s), nothing was submitted:\n%s", len(invalid), strings.Join(invalid, "\n"))
+	}
+
+	var expanded []string
+	for _, l := range links {
+		if l.list == nil {
+			expanded = append(expanded, l.video)
+			continue
+		}
+
+		feed, err := feeds.Fetch(ctx, *l.list)
+		if err != nil {
+			return nil, fmt.Errorf("expanding %s: %w", l.raw, err)
+		}
+		report(fmt.Sprintf("Expanded %s (%s) into %d video(s)", l.raw, feed.Title, len(feed.Entries)))
+		for _, e := range feed.Entries {
+			expanded = append(expanded, e.Video.URL())
+		}
+	}
+	return expanded, nil
+}
+
+// submitAll sends every URL to the podcast using at most concurrency
+// requests in flight. report is called once per URL as soon as its request
+// completes; results are returned in input order. Once ctx is done no new
+// requests are started and the remaining URLs fail with ctx's error.
+func submitAll(ctx context.Context, podcastID string, urls []string, concurrency int, report func(*submission)) []*submission {
+	subs := make([]*submission, len(urls))
+	for i, url := range urls {
+		subs[i] = &submission{URL: url}
+	}
+
+	jobs := make(chan *submission)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for range min(concurrency, len(subs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for sub := range jobs {
+				if err := ctx.Err(); err != nil {
+					sub.Err = err
+				} else {
+					sub.SubmittedAt = time.Now()
+					sub.Item, sub.Err = api.AddUrlToPodcast(ctx, podcastID, sub.URL)
+				}
+
+				mu.Lock()
+				report(sub)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, sub := range subs {
+		jobs <- sub
+	}
+	close(jobs)
+	wg.Wait()
+
+	return subs
+}
+
+// duplicate is a URL that shouldn't be submitted again and why.
+type duplicate struct {
+	URL    string
+	Reason string
+}
+
+// findDuplicates returns every URL that is already in the podcast, going by
+// its items and the local history, or that appears earlier in urls. The
+// URLs must be canonical. hist may be nil.
+func findDuplicates(ctx context.Context, podcastID string, urls []string, hist *history.Store) ([]duplicate, error) {
+	items, err := api.GetPodcastItems(ctx, podcastID)
+	if err != nil {
+		return nil, err
+	}
+
+	var dups []duplicate
+	seen := make(map[string]bool)
+	for _, url := range urls {
+		if seen[url] {
+			dups = append(dups, duplicate{URL: url, Reason: "listed more than once"})
+			continue
+		}
+		seen[url] = true
+
+		dup, ok := history.FindDuplicate(items, hist, podcastID, url)
+		if !ok {
+			continue
+		}
+		reason := "already added"
+		if !dup.AddedAt.IsZero() {
+			reason += " on " + dup.AddedAt.Local().Format("Jan 2, 2006")
+		}
+		dups = append(dups, duplicate{URL: url, Reason: reason})
+	}
+	return dups, nil
+}
+
+// withoutDuplicates drops the first occurrence of each duplicate from urls,
+// which keeps one copy of URLs listed more than once.
+func withoutDuplicates(urls []string, dups []duplicate) []string {
+	skip := make(map[string]int)
+	for _, dup := range dups {
+		skip[dup.URL]++
+	}
+	var kept []string
+	for _, url := range urls {
+		if skip[url] > 0 {
+			skip[url]--
+			continue
+		}
+		kept = append(kept, url)
+	}
+	return kept
+}
+
+func summarizeSubmissions(subs []*submission) (succeeded []*submission, failed int) {
+	for _, sub := range subs {
+		if sub.Err != nil {
+			failed++
+		} else {
+			succeeded = append(succeeded, sub)
+		}
+	}
+	return succeeded, failed
+}
+
+func submissionLine(sub *submission, podcastTitle string) string {
+	if sub.Err != nil {
+		return fmt.Sprintf("✗ %s: %v", sub.URL, sub.Err)
+	}
+	return fmt.Sprintf("✓ %s added to %s (status: %s)", sub.URL, podcastTitle, sub.Item.Status)
+}
@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/api/fake"
+	"github.com/lsherman98/yt-rss-cli/api/fakeserver"
+)
+
+const testAPIKey = "test-key"
+
+// A video whose URL contains "fail" ends in ERROR on the test server.
+const (
+	videoA      = "https://www.youtube.com/watch?v=aaaaaaaaaaa"
+	videoB      = "https://www.youtube.com/watch?v=bbbbbbbbbbb"
+	videoC      = "https://www.youtube.com/watch?v=ccccccccccc"
+	failing     = "https://www.youtube.com/watch?v=failfailfai"
+	existing    = "https://www.youtube.com/watch?v=SxdOUGdseq4"
+	podcastName = "Conference Talks"
+)
+
+type cli struct {
+	backend *fake.Service
+	server  *fakeserver.Server
+}
+
+// newCLI starts a fake server with one podcast holding one episode, and
+// points the config, history and API key of the commands run by c.run at
+// it.
+func newCLI(t *testing.T) *cli {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(dir, "state"))
+	t.Setenv("YTRSS_API_KEY", testAPIKey)
+	t.Setenv("YTRSS_BASE_URL", "")
+	t.Setenv("YTRSS_PROFILE", "")
+
+	backend := fake.New(testAPIKey)
+	backend.ProcessingDelay = 50 * time.Millisecond
+	backend.FailURL = func(url string) string {
+		if strings.Contains(url, "fail") {
+			return "video is unavailable"
+		}
+		return ""
+	}
+	podcast := backend.AddPodcast(podcastName)
+	backend.AddItem(podcast.ID, api.Item{Status: api.StatusSuccess, Title: "Simple Made Easy"}, existing, time.Now().Add(-time.Hour))
+
+	server := fakeserver.New(backend, testAPIKey)
+	t.Cleanup(server.Close)
+	return &cli{backend: backend, server: server}
+}
+
+// run executes ytrss with args and stdin and returns its output and exit
+// code.
+func (c *cli) run(t *testing.T, stdin string, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+	var out, errOut strings.Builder
+	root := NewRootCmd(BuildInfo{Version: "dev"})
+	root.SetArgs(append([]string{"--base-url", c.server.BaseURL(), "--retries", "0"}, args...))
+	root.SetIn(strings.NewReader(stdin))
+	root.SetOut(&out)
+	root.SetErr(&errOut)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	code = run(ctx, root, &errOut)
+	return out.String(), errOut.String(), code
+}
+
+func (c *cli) expect(t *testing.T, want int, stdin string, args ...string) (stdout, stderr string) {
+	t.Helper()
+	stdout, stderr, code := c.run(t, stdin, args...)
+	if code != want {
+		t.Fatalf("ytrss %s exited %d, want %d\nstdout:\n%s\nstderr:\n%s",
+			strings.Join(args, " "), code, want, stdout, stderr)
+	}
+	return stdout, stderr
+}
+
+func (c *cli) items(t *testing.T) []api.Item {
+	t.Helper()
+	items, err := c.backend.GetPodcastItems(context.Background(), "pod001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return items
+}
+
+func TestExitCodes(t *testing.T) {
+	c := newCLI(t)
+	fast := []string{"--wait", "--interval", "10ms"}
+
+	t.Run("ok", func(t *testing.T) {
+		c.expect(t, ExitOK, "", "podcasts", "list")
+	})
+	t.Run("failure", func(t *testing.T) {
+		t.Setenv("YTRSS_API_KEY", "wrong-key")
+		_, stderr := c.expect(t, ExitFailure, "", "podcasts", "list")
+		if !strings.Contains(stderr, "401") || !strings.Contains(stderr, "ytrss auth login") {
+			t.Errorf("stderr = %q, want the 401 and a login hint", stderr)
+		}
+	})
+	t.Run("item not retryable", func(t *testing.T) {
+		_, stderr := c.expect(t, ExitFailure, "", "items", "retry", podcastName, "missing")
+		if !strings.Contains(stderr, `item "missing" not found`) {
+			t.Errorf("stderr = %q, want the missing item", stderr)
+		}
+		_, stderr = c.expect(t, ExitFailure, "", "items", "retry", podcastName, c.items(t)[0].ID)
+		if !strings.Contains(stderr, "has not failed") {
+			t.Errorf("stderr = %q, want the item's status", stderr)
+		}
+	})
+	t.Run("podcast not found", func(t *testing.T) {
+		// Like a missing item, a missing podcast is a lookup miss, not a
+		// misused command.
+		_, stderr := c.expect(t, ExitFailure, "", "items", "No Such Podcast")
+		if !strings.Contains(stderr, `podcast "No Such Podcast" not found`) {
+			t.Errorf("stderr = %q, want the missing podcast", stderr)
+		}
+		c.expect(t, ExitFailure, "", "add", "--podcast", "No Such Podcast", videoA)
+	})
+	t.Run("items without ids", func(t *testing.T) {
+		c.backend.OmitItemIDs = true
+		defer func() { c.backend.OmitItemIDs = false }()
+		before := len(c.items(t))
+		_, stderr := c.expect(t, ExitFailure, "", "items", "delete", "--yes", podcastName, "")
+		if !strings.Contains(stderr, "have no ID and can't be addressed") {
+			t.Errorf("stderr = %q, want the items without an ID reported", stderr)
+		}
+		if after := len(c.items(t)); after != before {
+			t.Errorf("%d items left, want %d", after, before)
+		}
+	})
+	t.Run("usage", func(t *testing.T) {
+		c.expect(t, ExitUsage, "", "add", videoA)
+		c.expect(t, ExitUsage, "", "podcasts", "list", "--output", "xml")
+		c.expect(t, ExitUsage, "", "add", "--podcast", podcastName, "https://vimeo.com/12345")
+	})
+	t.Run("job failed", func(t *testing.T) {
+		c.expect(t, ExitJobFailed, "", append([]string{"add", "--podcast", podcastName, failing}, fast...)...)
+	})
+	t.Run("timeout", func(t *testing.T) {
+		c.backend.ProcessingDelay = time.Hour
+		defer func() { c.backend.ProcessingDelay = 50 * time.Millisecond }()
+		c.expect(t, ExitTimeout, "", append([]string{"add", "--podcast", podcastName, "--timeout", "100ms", videoC}, fast...)...)
+	})
+	t.Run("duplicate", func(t *testing.T) {
+		_, stderr := c.expect(t, ExitDuplicate, "", "add", "--podcast", podcastName, "https://youtu.be/SxdOUGdseq4")
+		if !strings.Contains(stderr, "already added") {
+			t.Errorf("stderr = %q, want the duplicate report", stderr)
+		}
+	})
+}
+
+func TestAddWait(t *testing.T) {
+	c := newCLI(t)
+
+	stdout, stderr := c.expect(t, ExitOK, "", "add", "--podcast", podcastName, "--wait", "--interval", "10ms", videoA, videoB)
+	if !strings.Contains(stderr, "Waiting for 2 episode(s)") {
+		t.Errorf("stderr = %q, want the waiting notice", stderr)
+	}
+	for _, url := range []string{videoA, videoB} {
+		if !strings.Contains(stdout, "✓ "+url) {
+			t.Errorf("stdout = %q, want a success line for %s", stdout, url)
+		}
+	}
+	for _, item := range c.items(t) {
+		if item.Status != api.StatusSuccess {
+			t.Errorf("item %s for %s is %s, want SUCCESS", item.ID, item.URL, item.Status)
+		}
+	}
+}
+
+func TestAddWaitFailure(t *testing.T) {
+	c := newCLI(t)
+
+	stdout, stderr := c.expect(t, ExitJobFailed, "", "add", "--podcast", podcastName, "--wait", "--interval", "10ms", videoA, failing)
+	if !strings.Contains(stdout, "✓ "+videoA) {
+		t.Errorf("stdout = %q, want the successful episode", stdout)
+	}
+	if !strings.Contains(stderr, failing+": video is unavailable") || !strings.Contains(stderr, "1 of 2 episode(s) failed") {
+		t.Errorf("stderr = %q, want the failed episode and a summary", stderr)
+	}
+}
+
+func TestAddWaitTimeout(t *testing.T) {
+	c := newCLI(t)
+	c.backend.ProcessingDelay = time.Hour
+
+	start := time.Now()
+	_, stderr := c.expect(t, ExitTimeout, "", "add", "--podcast", podcastName, "--wait", "--interval", "10ms", "--timeout", "100ms", videoA)
+	if !strings.Contains(stderr, videoA+": still CREATED") || !strings.Contains(stderr, "timed out after 100ms") {
+		t.Errorf("stderr = %q, want the pending episode and the timeout", stderr)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("add --timeout 100ms took %s", elapsed)
+	}
+}
+
+const urlList = `# Videos for this week
+https://www.youtube.com/watch?v=aaaaaaaaaaa
+
+https://youtu.be/bbbbbbbbbbb
+  https://www.youtube.com/shorts/ccccccccccc
+`
+
+func TestAddFromFile(t *testing.T) {
+	c := newCLI(t)
+	path := filepath.Join(t.TempDir(), "urls.txt")
+	if err := os.WriteFile(path, []byte(urlList), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr := c.expect(t, ExitOK, "", "add", "--podcast", podcastName, "--from-file", path, "--concurrency", "2")
+	checkBulk(t, c, stdout, stderr)
+}
+
+func TestAddFromStdin(t *testing.T) {
+	c := newCLI(t)
+
+	stdout, stderr := c.expect(t, ExitOK, urlList, "add", "--podcast", podcastName, "--from-file", "-")
+	checkBulk(t, c, stdout, stderr)
+}
+
+func checkBulk(t *testing.T, c *cli, stdout, stderr string) {
+	t.Helper()
+	if !strings.Contains(stderr, "Submitted 3 of 3 URL(s), 0 failed") {
+		t.Errorf("stderr = %q, want a summary of 3 submissions", stderr)
+	}
+	submitted := map[string]bool{}
+	for _, item := range c.items(t) {
+		submitted[item.URL] = true
+	}
+	for _, url := range []string{videoA, videoB, videoC} {
+		if !strings.Contains(stdout, url) {
+			t.Errorf("stdout = %q, want a line for %s", stdout, url)
+		}
+		if !submitted[url] {
+			t.Errorf("%s was not submitted in canonical form", url)
+		}
+	}
+}
+
+func TestAddFromFileOverQuota(t *testing.T) {
+	c := newCLI(t)
+	c.backend.SetLimit(c.backend.EpisodeSize)
+
+	stdout, stderr := c.expect(t, ExitFailure, videoA+"\n", "add", "--podcast", podcastName, "--from-file", "-", videoB, videoC)
+	if !strings.Contains(stderr, "3 of 3 URL(s) could not be submitted") {
+		t.Errorf("stderr = %q, want the failed submissions", stderr)
+	}
+	if strings.Count(stdout, "storage limit reached") != 3 {
+		t.Errorf("stdout = %q, want a failure line per URL", stdout)
+	}
+	if n := len(c.items(t)); n != 1 {
+		t.Errorf("podcast has %d items, want only the existing one", n)
+	}
+}
+
+func TestOutputJSON(t *testing.T) {
+	c := newCLI(t)
+
+	stdout, _ := c.expect(t, ExitOK, "", "podcasts", "list", "--output", "json")
+	var podcasts []api.Podcast
+	if err := json.Unmarshal([]byte(stdout), &podcasts); err != nil {
+		t.Fatalf("podcasts list -o json is not JSON: %v\n%s", err, stdout)
+	}
+	if len(podcasts) != 1 || podcasts[0].ID != "pod001" || podcasts[0].Title != podcastName {
+		t.Errorf("podcasts = %+v", podcasts)
+	}
+
+	stdout, _ = c.expect(t, ExitOK, "", "items", podcastName, "-o", "json")
+	var items []api.Item
+	if err := json.Unmarshal([]byte(stdout), &items); err != nil {
+		t.Fatalf("items -o json is not JSON: %v\n%s", err, stdout)
+	}
+	if len(items) != 1 || items[0].URL != existing || items[0].Status != api.StatusSuccess {
+		t.Errorf("items = %+v", items)
+	}
+
+	stdout, _ = c.expect(t, ExitOK, "", "usage", "-o", "json")
+	var usage api.UsageResponse
+	if err := json.Unmarshal([]byte(stdout), &usage); err != nil {
+		t.Fatalf("usage -o json is not JSON: %v\n%s", err, stdout)
+	}
+	if usage.Usage != c.backend.EpisodeSize {
+		t.Errorf("usage = %+v, want one episode", usage)
+	}
+}
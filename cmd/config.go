@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/lsherman98/yt-rss-cli/config"
+	"github.com/lsherman98/yt-rss-cli/output"
+	"github.com/spf13/cobra"
+)
+
+type configRow struct {
+	Key         string `json:"key" yaml:"key"`
+	Value       string `json:"value" yaml:"value"`
+	Source      string `json:"source" yaml:"source"`
+	Description string `json:"description" yaml:"description"`
+}
+
+var configColumns = []output.Column[configRow]{
+	{Name: "key", Value: func(r configRow) string { return r.Key }},
+	{Name: "value", Value: func(r configRow) string { return r.Value }},
+	{Name: "source", Value: func(r configRow) string { return r.Source }},
+	{Name: "description", Value: func(r configRow) string { return r.Description }},
+}
+
+func newConfigCmd(s *session) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Read and change settings",
+		Long: "Read and change the settings in the config file.\n\n" +
+			"Every setting can be overridden for a single run with an environment variable\n" +
+			"named after the key, e.g. YTRSS_UI_POLL_INTERVAL for ui.poll_interval, and\n" +
+			"network settings also with the matching command line flags. Run\n" +
+			"`ytrss config list` to see each key, its value and where the value came from.",
+		// The subcommands load the config themselves so that a broken file
+		// can still be inspected and fixed.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+
+	configCmd.AddCommand(
+		newConfigListCmd(),
+		newConfigGetCmd(),
+		newConfigSetCmd(),
+		newConfigEditCmd(),
+		newConfigPathCmd(),
+	)
+	return configCmd
+}
+
+func newConfigListCmd() *cobra.Command {
+	var opts output.Options
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List every setting with its effective value",
+		Args:    usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutput(opts); err != nil {
+				return err
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			settings, err := cfg.Resolve()
+			if err != nil {
+				return err
+			}
+
+			rows := make([]configRow, len(config.Keys))
+			for i, key := range config.Keys {
+				value, _ := settings.Get(key.Name)
+				rows[i] = configRow{
+					Key:         key.Name,
+					Value:       value,
+					Source:      settingSource(cfg, key),
+					Description: key.Description,
+				}
+			}
+			return output.List(cmd.OutOrStdout(), opts, rows, configColumns)
+		},
+	}
+
+	addOutputFlags(listCmd, &opts)
+	return listCmd
+}
+
+func settingSource(cfg *config.Config, key config.Key) string {
+	if v := os.Getenv(key.EnvVar()); v != "" {
+		return "env " + key.EnvVar()
+	}
+	if v, _ := cfg.Get(key.Name); v != "" {
+		return "file"
+	}
+	return "default"
+}
+
+func newConfigGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the effective value of a setting",
+		Args:  usageArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			settings, err := cfg.Resolve()
+			if err != nil {
+				return err
+			}
+
+			value, err := settings.Get(args[0])
+			if err != nil {
+				return &ExitError{Code: ExitUsage, Err: err}
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), value)
+			return nil
+		},
+	}
+}
+
+func newConfigSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Change a setting in the config file",
+		Long:  "Validate and save a setting. Pass an empty value (\"\") to reset it to the default.",
+		Args:  usageArgs(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if err := cfg.Set(args[0], args[1]); err != nil {
+				return &ExitError{Code: ExitUsage, Err: err}
+			}
+			return config.Save(cfg)
+		},
+	}
+}
+
+func newConfigPathCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "path",
+		Short: "Print the location of the config file",
+		Args:  usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.Path()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), path)
+			return nil
+		},
+	}
+}
+
+func newConfigEditCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Open the config file in $VISUAL or $EDITOR",
+		Long: "Open a copy of the config file in $VISUAL or $EDITOR. The file is only\n" +
+			"replaced if the edited copy is valid.",
+		Args: usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.Path()
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+				return err
+			}
+
+			draft, err := os.CreateTemp(filepath.Dir(path), "config-edit-*.toml")
+			if err != nil {
+				return err
+			}
+			draftPath := draft.Name()
+			if current, err := os.Open(path); err == nil {
+				_, err = io.Copy(draft, current)
+				current.Close()
+				if err != nil {
+					draft.Close()
+					return err
+				}
+			} else if !errors.Is(err, os.ErrNotExist) {
+				draft.Close()
+				return err
+			}
+			if err := draft.Close(); err != nil {
+				return err
+			}
+
+			editorArgs := append(strings.Fields(editorCommand()), draftPath)
+			editor := exec.Command(editorArgs[0], editorArgs[1:]...)
+			editor.Stdin = os.Stdin
+			editor.Stdout = os.Stdout
+			editor.Stderr = os.Stderr
+			if err := editor.Run(); err != nil {
+				os.Remove(draftPath)
+				return fmt.Errorf("running editor: %w", err)
+			}
+
+			cfg, err := config.LoadFile(draftPath)
+			if err == nil {
+				_, err = cfg.Resolve()
+			}
+			if err != nil {
+				return fmt.Errorf("%w\nYour changes were not applied; they are saved in %s", err, draftPath)
+			}
+			return os.Rename(draftPath, path)
+		},
+	}
+}
+
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(env); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
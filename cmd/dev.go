@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api/fake"
+	"github.com/lsherman98/yt-rss-cli/api/fakeserver"
+	"github.com/spf13/cobra"
+)
+
+func newDevCmd() *cobra.Command {
+	devCmd := &cobra.Command{
+		Use:   "dev",
+		Short: "Tools for developing and demoing the CLI",
+		Args:  usageArgs(cobra.NoArgs),
+		// Nothing here talks to the real API, so skip loading the profile.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+
+	devCmd.AddCommand(newDevFakeServerCmd())
+	return devCmd
+}
+
+func newDevFakeServerCmd() *cobra.Command {
+	var (
+		addr            string
+		apiKey          string
+		processingDelay time.Duration
+		latency         time.Duration
+		limitMB         int
+		failSubstring   string
+		empty           bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "fake-server",
+		Short: "Serve a fake ytrss.xyz API for offline demos and tests",
+		Long: `Serve a fake ytrss.xyz API from memory.
+
+Submitted URLs stay CREATED for --processing-delay and then become SUCCESS,
+or ERROR when they contain --fail-substring. Each successful episode counts
+50 MB towards --limit-mb. Point the CLI at it with --base-url and the
+printed API key.`,
+		Args: usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			backend := fake.New(apiKey)
+			if !empty {
+				backend = fake.NewDemo()
+			}
+			backend.ProcessingDelay = processingDelay
+			backend.FailURL = func(url string) string {
+				if failSubstring != "" && strings.Contains(url, failSubstring) {
+					return "video is unavailable"
+				}
+				return ""
+			}
+			backend.SetLimit(limitMB * 1024 * 1024)
+
+			handler := fakeserver.NewHandler(backend, apiKey)
+			handler.Latency = latency
+
+			ln, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+			srv := &http.Server{Handler: handler}
+			go func() {
+				<-cmd.Context().Done()
+				srv.Close()
+			}()
+
+			baseURL := "http://" + ln.Addr().String() + fakeserver.BasePath
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Serving fake API at %s\n", baseURL)
+			fmt.Fprintf(out, "API key: %s\n\n", apiKey)
+			fmt.Fprintf(out, "Try: YTRSS_API_KEY=%s ytrss --base-url %s\n", apiKey, baseURL)
+			fmt.Fprintln(out, "Press Ctrl+C to stop.")
+
+			if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&addr, "addr", "127.0.0.1:8787", "address to listen on")
+	flags.StringVar(&apiKey, "api-key", "demo-key", "API key clients must send")
+	flags.DurationVar(&processingDelay, "processing-delay", 5*time.Second, "how long submitted URLs stay CREATED")
+	flags.DurationVar(&latency, "latency", 0, "delay added to every response")
+	flags.IntVar(&limitMB, "limit-mb", 10*1024, "storage limit in MB")
+	flags.StringVar(&failSubstring, "fail-substring", "fail", "URLs containing this end in ERROR")
+	flags.BoolVar(&empty, "empty", false, "start with no podcasts instead of demo data")
+	return cmd
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/config"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes returned by the headless commands. Scripts can rely on these
+// staying stable.
+const (
+	ExitOK        = 0
+	ExitFailure   = 1
+	ExitUsage     = 2
+	ExitJobFailed = 3
+	ExitTimeout   = 4
+	ExitDuplicate = 5
+)
+
+// ExitError carries a specific process exit code. A nil Err means the
+// command has already reported the problem and only the code matters.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return ExitFailure
+}
+
+// errorHint suggests a next step for API errors the user can act on.
+func errorHint(err error) string {
+	switch {
+	case api.IsUnauthorized(err):
+		return "Run `ytrss auth login` to set a valid API key."
+	case api.IsQuotaExceeded(err):
+		return "You have reached your usage limit. Delete some episodes or upgrade your plan on ytrss.xyz."
+	case errors.Is(err, api.ErrDefaultHTTPS):
+		return "Pass --allow-insecure-http or set " + config.EnvAllowInsecureHTTP + "=true to use " + api.InsecureDefaultBaseURL + " as before."
+	}
+	return ""
+}
+
+func usageError(format string, args ...any) error {
+	return &ExitError{Code: ExitUsage, Err: fmt.Errorf(format, args...)}
+}
+
+// usageArgs wraps a cobra positional argument validator so that violations
+// exit with ExitUsage.
+func usageArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := validate(cmd, args); err != nil {
+			return &ExitError{Code: ExitUsage, Err: err}
+		}
+		return nil
+	}
+}
+
+// validateFlags checks required and mutually exclusive flags. Cobra does
+// this itself only after the pre-run hooks and returns a plain error, which
+// would exit with ExitFailure.
+func validateFlags(cmd *cobra.Command) error {
+	if err := cmd.ValidateRequiredFlags(); err != nil {
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+	if err := cmd.ValidateFlagGroups(); err != nil {
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,406 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/history"
+	"github.com/lsherman98/yt-rss-cli/output"
+	"github.com/spf13/cobra"
+)
+
+func newItemsCmd() *cobra.Command {
+	var opts output.Options
+
+	itemsCmd := &cobra.Command{
+		Use:   "items <podcast>",
+		Short: "List the episodes of a podcast",
+		Long:  "List the episodes of a podcast, newest first. The podcast may be given by ID or title.",
+		Args:  usageArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutput(opts); err != nil {
+				return err
+			}
+
+			podcast, err := resolvePodcast(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			items, err := api.GetPodcastItems(cmd.Context(), podcast.ID)
+			if err != nil {
+				return err
+			}
+			sortItemsNewestFirst(items)
+
+			return output.List(cmd.OutOrStdout(), opts, items, itemColumns)
+		},
+	}
+
+	addOutputFlags(itemsCmd, &opts)
+	itemsCmd.AddCommand(newItemsRetryCmd())
+	itemsCmd.AddCommand(newItemsEditCmd())
+	itemsCmd.AddCommand(newItemsDeleteCmd())
+	itemsCmd.AddCommand(newItemsHideCmd(true))
+	itemsCmd.AddCommand(newItemsHideCmd(false))
+	return itemsCmd
+}
+
+func newItemsRetryCmd() *cobra.Command {
+	var (
+		failed      bool
+		concurrency int
+		wait        bool
+		timeout     time.Duration
+		interval    time.Duration
+	)
+
+	retryCmd := &cobra.Command{
+		Use:   "retry <podcast> (--failed | <item-id>...)",
+		Short: "Resubmit episodes that failed to process",
+		Long: "Resubmit the videos of items that ended in ERROR. Pass the IDs of the items\n" +
+			"to retry, or --failed to retry every failed item that hasn't been resubmitted\n" +
+			"successfully since.\n\n" +
+			"The video is taken from the item's source URL, or from the local history when\n" +
+			"the server doesn't report one. Items whose video is unknown are reported and\n" +
+			"make the command exit 1; add them again with `ytrss add`.\n\n" +
+			"With --wait the command blocks until the resubmitted episodes finish\n" +
+			"processing, with the same exit codes as `ytrss add --wait`.",
+		Example: "  ytrss items retry \"My Talks\" --failed --wait\n" +
+			"  ytrss items retry abc123 item42",
+		Args: usageArgs(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids := args[1:]
+			if failed == (len(ids) > 0) {
+				return usageError("pass either item IDs or --failed")
+			}
+			if interval <= 0 {
+				return usageError("--interval must be positive")
+			}
+			if concurrency < 1 {
+				return usageError("--concurrency must be at least 1")
+			}
+
+			podcast, err := resolvePodcast(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			hist, err := history.Load()
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: items without a source URL can't be retried: reading history: %v\n", err)
+				hist = nil
+			}
+			items, err := api.GetPodcastItems(cmd.Context(), podcast.ID)
+			if err != nil {
+				return err
+			}
+
+			var retries []history.Retry
+			if failed {
+				retries = history.FailedItems(items, hist, podcast.ID)
+			} else if retries, err = selectRetries(items, hist, podcast.ID, ids); err != nil {
+				return err
+			}
+			if len(retries) == 0 {
+				fmt.Fprintln(cmd.ErrOrStderr(), "No failed items to retry.")
+				return nil
+			}
+
+			var urls []string
+			unknown := 0
+			for _, r := range retries {
+				if r.URL == "" {
+					unknown++
+					fmt.Fprintf(cmd.ErrOrStderr(), "✗ %s: the video it was created from is unknown, add it again with `ytrss add`\n", itemLabel(r.Item))
+					continue
+				}
+				urls = append(urls, r.URL)
+			}
+
+			subs := submitAll(cmd.Context(), podcast.ID, urls, concurrency, func(sub *submission) {
+				line := submissionLine(sub, podcast.Title)
+				if sub.Err == nil && hist != nil {
+					n, err := hist.RecordRetry(podcast.ID, sub.URL, sub.Item.ID, sub.SubmittedAt)
+					if err != nil {
+						fmt.Fprintf(cmd.ErrOrStderr(), "Warning: saving history: %v\n", err)
+					} else if n > 0 {
+						line += fmt.Sprintf(" (retry %d)", n)
+					}
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), line)
+			})
+
+			succeeded, failedSubs := summarizeSubmissions(subs)
+			failedSubs += unknown
+			if len(retries) > 1 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Resubmitted %d of %d item(s), %d failed\n", len(succeeded), len(retries), failedSubs)
+			}
+
+			if wait && len(succeeded) > 0 {
+				if err := waitAndReport(cmd, podcast.ID, succeeded, timeout, interval); err != nil {
+					return err
+				}
+			}
+
+			if failedSubs > 0 {
+				return &ExitError{Code: ExitFailure, Err: fmt.Errorf("%d of %d item(s) could not be resubmitted", failedSubs, len(retries))}
+			}
+			return nil
+		},
+	}
+
+	retryCmd.Flags().BoolVar(&failed, "failed", false, "retry every failed item")
+	retryCmd.Flags().IntVarP(&concurrency, "concurrency", "c", 4, "maximum number of videos submitted at once")
+	retryCmd.Flags().BoolVarP(&wait, "wait", "w", false, "wait until the episodes finish processing")
+	retryCmd.Flags().DurationVar(&timeout, "timeout", 0, "give up waiting after this long, e.g. 10m (0 waits forever)")
+	retryCmd.Flags().DurationVar(&interval, "interval", 3*time.Second, "how often to poll while waiting")
+
+	return retryCmd
+}
+
+func newItemsEditCmd() *cobra.Command {
+	var (
+		opts                          output.Options
+		title, description, published string
+	)
+
+	editCmd := &cobra.Command{
+		Use:   "edit <podcast> <item-id>",
+		Short: "Change the title, description or publish date of an episode",
+		Long: "Change the title, description or publish date of an episode. Only the fields\n" +
+			"given are changed. The feed is ordered by publish date, so changing it moves\n" +
+			"the episode; pass --published \"\" to go back to the date it was added.",
+		Example: "  ytrss items edit \"My Talks\" item42 --title \"Keynote\" --published 2026-01-15",
+		Args:    usageArgs(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutput(opts); err != nil {
+				return err
+			}
+
+			body := api.UpdateItemRequestBody{ItemID: args[1]}
+			flags := cmd.Flags()
+			if flags.Changed("title") {
+				if strings.TrimSpace(title) == "" {
+					return usageError("the title can't be empty")
+				}
+				body.Title = &title
+			}
+			if flags.Changed("description") {
+				body.Description = &description
+			}
+			if flags.Changed("published") {
+				if published != "" {
+					t, err := api.ParseDate(published)
+					if err != nil {
+						return usageError("--published: %v", err)
+					}
+					published = t.UTC().Format(time.RFC3339)
+				}
+				body.Published = &published
+			}
+			if body.Title == nil && body.Description == nil && body.Published == nil {
+				return usageError("nothing to change, pass at least one of --title, --description or --published")
+			}
+
+			podcast, err := resolvePodcast(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			body.PodcastID = podcast.ID
+
+			item, err := api.UpdateItem(cmd.Context(), body)
+			if err != nil {
+				return err
+			}
+			return output.Object(cmd.OutOrStdout(), opts, item, itemColumns)
+		},
+	}
+
+	editCmd.Flags().StringVar(&title, "title", "", "new title")
+	editCmd.Flags().StringVar(&description, "description", "", "description shown in podcast apps")
+	editCmd.Flags().StringVar(&published, "published", "", "publish date, as YYYY-MM-DD, YYYY-MM-DD HH:MM or RFC 3339")
+	addOutputFlags(editCmd, &opts)
+	return editCmd
+}
+
+func newItemsDeleteCmd() *cobra.Command {
+	var yes bool
+
+	deleteCmd := &cobra.Command{
+		Use:     "delete <podcast> <item-id>...",
+		Aliases: []string{"rm"},
+		Short:   "Delete episodes from a podcast",
+		Long: "Delete episodes and their audio from a podcast. They disappear from the feed\n" +
+			"and no longer count towards your storage. This can't be undone, so the\n" +
+			"command asks first; pass --yes to skip the question, which is required when\n" +
+			"stdin isn't a terminal.",
+		Args: usageArgs(cobra.MinimumNArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			podcast, err := resolvePodcast(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			all, err := api.GetPodcastItems(cmd.Context(), podcast.ID)
+			if err != nil {
+				return err
+			}
+			items, err := findItems(all, args[1:])
+			if err != nil {
+				return err
+			}
+
+			if !yes {
+				in, ok := terminalStdin(cmd)
+				if !ok {
+					return usageError("refusing to delete without confirmation, pass --yes")
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Delete %d episode(s) from %s? [y/N] ", len(items), podcast.Title)
+				answer, err := readLine(in)
+				if err != nil {
+					return err
+				}
+				if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+					return &ExitError{Code: ExitFailure, Err: errors.New("nothing was deleted")}
+				}
+			}
+
+			hist, err := history.Load()
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: reading history: %v\n", err)
+				hist = nil
+			}
+			return changeItems(cmd, items, "Deleted", func(item api.Item) error {
+				if err := api.DeleteItem(cmd.Context(), podcast.ID, item.ID); err != nil {
+					return err
+				}
+				if hist != nil {
+					if err := hist.Forget(podcast.ID, item.ID); err != nil {
+						fmt.Fprintf(cmd.ErrOrStderr(), "Warning: saving history: %v\n", err)
+					}
+				}
+				return nil
+			})
+		},
+	}
+
+	deleteCmd.Flags().BoolVarP(&yes, "yes", "y", false, "delete without asking for confirmation")
+	return deleteCmd
+}
+
+// newItemsHideCmd returns the hide command, or unhide when hidden is false.
+func newItemsHideCmd(hidden bool) *cobra.Command {
+	use, short, done := "hide", "Leave episodes out of the feed without deleting them", "Hid"
+	if !hidden {
+		use, short, done = "unhide", "Put hidden episodes back in the feed", "Unhid"
+	}
+
+	return &cobra.Command{
+		Use:   use + " <podcast> <item-id>...",
+		Short: short,
+		Args:  usageArgs(cobra.MinimumNArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			podcast, err := resolvePodcast(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			all, err := api.GetPodcastItems(cmd.Context(), podcast.ID)
+			if err != nil {
+				return err
+			}
+			items, err := findItems(all, args[1:])
+			if err != nil {
+				return err
+			}
+
+			return changeItems(cmd, items, done, func(item api.Item) error {
+				_, err := api.UpdateItem(cmd.Context(), api.UpdateItemRequestBody{
+					PodcastID: podcast.ID,
+					ItemID:    item.ID,
+					Hidden:    &hidden,
+				})
+				return err
+			})
+		},
+	}
+}
+
+// changeItems applies change to each item in turn, reporting every one. It
+// fails when any of them did.
+func changeItems(cmd *cobra.Command, items []api.Item, done string, change func(api.Item) error) error {
+	failed := 0
+	for _, item := range items {
+		if err := change(item); err != nil {
+			failed++
+			fmt.Fprintf(cmd.ErrOrStderr(), "✗ %s: %v\n", itemLabel(item), err)
+			continue
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "✓ %s %s\n", done, itemLabel(item))
+	}
+	if failed > 0 {
+		return &ExitError{Code: ExitFailure, Err: fmt.Errorf("%d of %d item(s) failed", failed, len(items))}
+	}
+	return nil
+}
+
+// findItems looks up items by ID. Every item must exist. Items the server
+// listed without an ID can't be addressed and are never found.
+func findItems(items []api.Item, ids []string) ([]api.Item, error) {
+	byID := make(map[string]api.Item, len(items))
+	unnamed := 0
+	for _, item := range items {
+		if item.ID == "" {
+			unnamed++
+			continue
+		}
+		byID[item.ID] = item
+	}
+
+	var found []api.Item
+	for _, id := range ids {
+		item, ok := byID[id]
+		if !ok {
+			err := fmt.Errorf("item %q not found", id)
+			if unnamed > 0 {
+				err = fmt.Errorf("%w; %d item(s) in the podcast have no ID and can't be addressed", err, unnamed)
+			}
+			return nil, &ExitError{Code: ExitFailure, Err: err}
+		}
+		found = append(found, item)
+	}
+	return found, nil
+}
+
+// selectRetries looks up the items to retry by ID. Every item must exist
+// and have failed.
+func selectRetries(items []api.Item, hist *history.Store, podcastID string, ids []string) ([]history.Retry, error) {
+	found, err := findItems(items, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var retries []history.Retry
+	for _, item := range found {
+		if item.Status != api.StatusError {
+			return nil, &ExitError{Code: ExitFailure, Err: fmt.Errorf("item %q has not failed (status: %s)", item.ID, item.Status)}
+		}
+		retries = append(retries, history.Retry{Item: item, URL: history.SourceURL(hist, podcastID, item)})
+	}
+	return retries, nil
+}
+
+func itemLabel(item api.Item) string {
+	if item.Title != "" {
+		return fmt.Sprintf("%s (%s)", item.ID, item.Title)
+	}
+	return item.ID
+}
+
+func sortItemsNewestFirst(items []api.Item) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreatedAt().After(items[j].CreatedAt())
+	})
+}
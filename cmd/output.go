@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/output"
+	"github.com/spf13/cobra"
+)
+
+func addOutputFlags(cmd *cobra.Command, opts *output.Options) {
+	opts.Format = output.FormatTable
+	cmd.Flags().StringVarP((*string)(&opts.Format), "output", "o", string(output.FormatTable),
+		"output format: table, json, yaml, csv or tsv")
+	cmd.Flags().StringVar(&opts.Template, "format", "",
+		"Go template applied to each result, e.g. '{{.Title}}' (overrides --output)")
+}
+
+func validateOutput(opts output.Options) error {
+	if err := opts.Validate(); err != nil {
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+	return nil
+}
+
+var podcastColumns = []output.Column[api.Podcast]{
+	{Name: "id", Value: func(p api.Podcast) string { return p.ID }},
+	{Name: "title", Value: func(p api.Podcast) string { return p.Title }},
+}
+
+// podcastDetailColumns show every field of a podcast, for the commands that
+// print a single one.
+var podcastDetailColumns = append(podcastColumns[:len(podcastColumns):len(podcastColumns)],
+	output.Column[api.Podcast]{Name: "author", Value: func(p api.Podcast) string { return p.Author }},
+	output.Column[api.Podcast]{Name: "category", Value: func(p api.Podcast) string { return p.Category }},
+	output.Column[api.Podcast]{Name: "artwork", Value: func(p api.Podcast) string { return p.Artwork }},
+	output.Column[api.Podcast]{Name: "description", Value: func(p api.Podcast) string { return p.Description }},
+)
+
+var itemColumns = []output.Column[api.Item]{
+	{Name: "id", Value: func(i api.Item) string { return i.ID }},
+	{Name: "title", Value: func(i api.Item) string { return i.Title }},
+	{Name: "status", Value: func(i api.Item) string { return i.Status }},
+	{
+		Name:  "created",
+		Value: func(i api.Item) string { return i.Created },
+		Pretty: func(i api.Item) string {
+			if t := i.CreatedAt(); !t.IsZero() {
+				return t.Local().Format("Jan 2, 2006 3:04 PM")
+			}
+			return i.Created
+		},
+	},
+	{Name: "error", Value: func(i api.Item) string { return i.Error }},
+	{
+		Name:  "hidden",
+		Value: func(i api.Item) string { return strconv.FormatBool(i.Hidden) },
+		Pretty: func(i api.Item) string {
+			if i.Hidden {
+				return "yes"
+			}
+			return ""
+		},
+	},
+}
+
+var usageColumns = []output.Column[api.UsageResponse]{
+	{
+		Name:   "usage",
+		Value:  func(u api.UsageResponse) string { return strconv.Itoa(u.Usage) },
+		Pretty: func(u api.UsageResponse) string { return output.FormatBytes(u.Usage) },
+	},
+	{
+		Name:   "limit",
+		Value:  func(u api.UsageResponse) string { return strconv.Itoa(u.Limit) },
+		Pretty: func(u api.UsageResponse) string { return output.FormatBytes(u.Limit) },
+	},
+	{
+		Name: "percent",
+		Value: func(u api.UsageResponse) string {
+			return strconv.FormatFloat(usagePercent(u), 'f', 1, 64)
+		},
+		Pretty: func(u api.UsageResponse) string {
+			return fmt.Sprintf("%.1f%%", usagePercent(u))
+		},
+	},
+}
+
+func usagePercent(u api.UsageResponse) float64 {
+	if u.Limit <= 0 {
+		return 0
+	}
+	return float64(u.Usage) / float64(u.Limit) * 100
+}
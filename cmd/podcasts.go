@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/output"
+	"github.com/spf13/cobra"
+)
+
+func newPodcastsCmd() *cobra.Command {
+	podcastsCmd := &cobra.Command{
+		Use:   "podcasts",
+		Short: "Manage your podcasts",
+	}
+
+	podcastsCmd.AddCommand(newPodcastsListCmd())
+	podcastsCmd.AddCommand(newPodcastsCreateCmd())
+	podcastsCmd.AddCommand(newPodcastsEditCmd())
+	podcastsCmd.AddCommand(newPodcastsDeleteCmd())
+
+	return podcastsCmd
+}
+
+func newPodcastsListCmd() *cobra.Command {
+	var opts output.Options
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List your podcasts",
+		Args:    usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutput(opts); err != nil {
+				return err
+			}
+
+			podcasts, err := api.ListPodcasts(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			return output.List(cmd.OutOrStdout(), opts, podcasts, podcastColumns)
+		},
+	}
+
+	addOutputFlags(listCmd, &opts)
+	return listCmd
+}
+
+func newPodcastsCreateCmd() *cobra.Command {
+	var (
+		opts output.Options
+		body api.CreatePodcastRequestBody
+	)
+
+	createCmd := &cobra.Command{
+		Use:     "create <title>",
+		Short:   "Create a podcast",
+		Example: "  ytrss podcasts create \"My Talks\" --author \"Jane Doe\" --category Technology",
+		Args:    usageArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutput(opts); err != nil {
+				return err
+			}
+			body.Title = strings.TrimSpace(args[0])
+			if body.Title == "" {
+				return usageError("the title can't be empty")
+			}
+
+			podcast, err := api.CreatePodcast(cmd.Context(), body)
+			if err != nil {
+				return err
+			}
+			return output.Object(cmd.OutOrStdout(), opts, podcast, podcastDetailColumns)
+		},
+	}
+
+	createCmd.Flags().StringVar(&body.Description, "description", "", "description shown in podcast apps")
+	createCmd.Flags().StringVar(&body.Author, "author", "", "author shown in podcast apps")
+	createCmd.Flags().StringVar(&body.Artwork, "artwork", "", "URL of the cover image")
+	createCmd.Flags().StringVar(&body.Category, "category", "", "category, e.g. Technology")
+	addOutputFlags(createCmd, &opts)
+	return createCmd
+}
+
+func newPodcastsEditCmd() *cobra.Command {
+	var (
+		opts                                          output.Options
+		title, description, author, artwork, category string
+	)
+
+	editCmd := &cobra.Command{
+		Use:   "edit <podcast>",
+		Short: "Rename a podcast or change its details",
+		Long: "Change the title, description, author, artwork or category of a podcast. Only\n" +
+			"the fields given are changed; pass an empty value to clear one.",
+		Example: "  ytrss podcasts edit \"My Talks\" --title \"Conference Talks\"\n" +
+			"  ytrss podcasts edit abc123 --artwork https://example.com/cover.jpg",
+		Args: usageArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutput(opts); err != nil {
+				return err
+			}
+
+			var body api.UpdatePodcastRequestBody
+			flags := cmd.Flags()
+			if flags.Changed("title") {
+				body.Title = &title
+			}
+			if flags.Changed("description") {
+				body.Description = &description
+			}
+			if flags.Changed("author") {
+				body.Author = &author
+			}
+			if flags.Changed("artwork") {
+				body.Artwork = &artwork
+			}
+			if flags.Changed("category") {
+				body.Category = &category
+			}
+			if body == (api.UpdatePodcastRequestBody{}) {
+				return usageError("nothing to change, pass at least one of --title, --description, --author, --artwork or --category")
+			}
+			if body.Title != nil && strings.TrimSpace(*body.Title) == "" {
+				return usageError("the title can't be empty")
+			}
+
+			podcast, err := resolvePodcast(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			body.PodcastID = podcast.ID
+
+			podcast, err = api.UpdatePodcast(cmd.Context(), body)
+			if err != nil {
+				return err
+			}
+			return output.Object(cmd.OutOrStdout(), opts, podcast, podcastDetailColumns)
+		},
+	}
+
+	editCmd.Flags().StringVar(&title, "title", "", "new title")
+	editCmd.Flags().StringVar(&description, "description", "", "description shown in podcast apps")
+	editCmd.Flags().StringVar(&author, "author", "", "author shown in podcast apps")
+	editCmd.Flags().StringVar(&artwork, "artwork", "", "URL of the cover image")
+	editCmd.Flags().StringVar(&category, "category", "", "category, e.g. Technology")
+	addOutputFlags(editCmd, &opts)
+	return editCmd
+}
+
+func newPodcastsDeleteCmd() *cobra.Command {
+	var yes bool
+
+	deleteCmd := &cobra.Command{
+		Use:     "delete <podcast>",
+		Aliases: []string{"rm"},
+		Short:   "Delete a podcast and all of its episodes",
+		Long: "Delete a podcast, its episodes and its public feed. This can't be undone, so\n" +
+			"the podcast's title must be typed to confirm. Pass --yes to skip the prompt,\n" +
+			"which is required when stdin isn't a terminal.",
+		Args: usageArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			podcast, err := resolvePodcast(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			if !yes {
+				in, ok := terminalStdin(cmd)
+				if !ok {
+					return usageError("refusing to delete %q without confirmation, pass --yes", podcast.Title)
+				}
+				what := "all of its episodes"
+				if items, err := api.GetPodcastItems(cmd.Context(), podcast.ID); err == nil {
+					what = fmt.Sprintf("its %d episode(s)", len(items))
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "This deletes %s and %s. Type the podcast's title to confirm: ", podcast.Title, what)
+				typed, err := readLine(in)
+				if err != nil {
+					return err
+				}
+				if typed != podcast.Title {
+					return &ExitError{Code: ExitFailure, Err: fmt.Errorf("the title didn't match, %q was not deleted", podcast.Title)}
+				}
+			}
+
+			if err := api.DeletePodcast(cmd.Context(), podcast.ID); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Podcast %s deleted.\n", podcast.Title)
+			return nil
+		},
+	}
+
+	deleteCmd.Flags().BoolVarP(&yes, "yes", "y", false, "delete without asking for confirmation")
+	return deleteCmd
+}
+
+// terminalStdin returns stdin when someone can answer a prompt on it.
+func terminalStdin(cmd *cobra.Command) (*os.File, bool) {
+	in, ok := cmd.InOrStdin().(*os.File)
+	return in, ok && term.IsTerminal(in.Fd())
+}
+
+func readLine(in io.Reader) (string, error) {
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// resolvePodcast finds a podcast by ID or, failing that, by case-insensitive
+// title. Ambiguous titles are rejected so scripts never act on the wrong feed.
+func resolvePodcast(ctx context.Context, query string) (api.Podcast, error) {
+	podcasts, err := api.ListPodcasts(ctx)
+	if err != nil {
+		return api.Podcast{}, err
+	}
+
+	for _, p := range podcasts {
+		if p.ID == query {
+			return p, nil
+		}
+	}
+
+	var matches []api.Podcast
+	for _, p := range podcasts {
+		if strings.EqualFold(p.Title, query) {
+			matches = append(matches, p)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return api.Podcast{}, &ExitError{Code: ExitFailure, Err: fmt.Errorf("podcast %q not found", query)}
+	case 1:
+		return matches[0], nil
+	default:
+		return api.Podcast{}, &ExitError{Code: ExitFailure, Err: fmt.Errorf("podcast title %q is ambiguous, use the podcast ID instead", query)}
+	}
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/config"
+	"github.com/lsherman98/yt-rss-cli/output"
+	"github.com/spf13/cobra"
+)
+
+type profileRow struct {
+	Name    string `json:"name" yaml:"name"`
+	BaseURL string `json:"base_url" yaml:"base_url"`
+	Active  bool   `json:"active" yaml:"active"`
+}
+
+var profileColumns = []output.Column[profileRow]{
+	{
+		Name:  "name",
+		Value: func(p profileRow) string { return p.Name },
+		Pretty: func(p profileRow) string {
+			if p.Active {
+				return p.Name + " *"
+			}
+			return p.Name
+		},
+	},
+	{Name: "base_url", Value: func(p profileRow) string { return p.BaseURL }},
+	{Name: "active", Value: func(p profileRow) string { return fmt.Sprint(p.Active) }},
+}
+
+func newProfilesCmd(s *session) *cobra.Command {
+	profilesCmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "Manage named profiles for multiple accounts",
+		Long: "Profiles let you switch between several ytrss.xyz accounts. Each profile has\n" +
+			"its own API key and base URL. Select one per command with --profile or\n" +
+			"$" + config.EnvProfile + ", or make it the default with `ytrss profiles use`.\n\n" +
+			"Set a profile's API key with `ytrss --profile <name> auth login`.",
+		// Only load the config: the selected profile may not exist yet.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return s.loadConfig()
+		},
+	}
+
+	profilesCmd.AddCommand(
+		newProfilesListCmd(s),
+		newProfilesAddCmd(s),
+		newProfilesUseCmd(s),
+		newProfilesRemoveCmd(s),
+	)
+	return profilesCmd
+}
+
+func newProfilesListCmd(s *session) *cobra.Command {
+	var opts output.Options
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List profiles; the active one is marked with *",
+		Args:    usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutput(opts); err != nil {
+				return err
+			}
+
+			var rows []profileRow
+			for _, name := range s.cfg.ProfileNames() {
+				profile, _ := s.cfg.Profile(name)
+				baseURL := profile.BaseURL
+				if baseURL == "" {
+					baseURL = api.DefaultBaseURL
+				}
+				rows = append(rows, profileRow{Name: name, BaseURL: baseURL, Active: name == s.profile})
+			}
+			return output.List(cmd.OutOrStdout(), opts, rows, profileColumns)
+		},
+	}
+
+	addOutputFlags(listCmd, &opts)
+	return listCmd
+}
+
+func newProfilesAddCmd(s *session) *cobra.Command {
+	var profile config.Profile
+
+	addCmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Create or update a profile",
+		Args:  usageArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if profile.BaseURL != "" {
+				if err := api.ValidateBaseURL(profile.BaseURL, profile.AllowInsecureHTTP); err != nil {
+					return &ExitError{Code: ExitUsage, Err: err}
+				}
+			}
+			if err := s.cfg.SetProfile(name, profile); err != nil {
+				return &ExitError{Code: ExitUsage, Err: err}
+			}
+			if err := config.Save(s.cfg); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Profile %s saved. Set its API key with `ytrss --profile %s auth login`.\n", name, name)
+			return nil
+		},
+	}
+
+	addCmd.Flags().StringVar(&profile.BaseURL, "base-url", "", "API base URL for this profile (default "+api.DefaultBaseURL+")")
+	addCmd.Flags().BoolVar(&profile.AllowInsecureHTTP, "allow-insecure-http", false,
+		"allow a plain http:// base URL on a host other than localhost")
+	return addCmd
+}
+
+func newProfilesUseCmd(s *session) *cobra.Command {
+	return &cobra.Command{
+		Use:   "use <name>",
+		Short: "Make a profile the default for future commands",
+		Args:  usageArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := s.Switch(args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Switched to profile %s.\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newProfilesRemoveCmd(s *session) *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <name>",
+		Aliases: []string{"rm"},
+		Short:   "Delete a profile",
+		Long:    "Delete a profile from the config file. Its stored API key is not removed; run\n`ytrss --profile <name> auth logout` first to clear it.",
+		Args:    usageArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := s.cfg.RemoveProfile(args[0]); err != nil {
+				return &ExitError{Code: ExitUsage, Err: err}
+			}
+			if err := config.Save(s.cfg); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Profile %s removed.\n", args[0])
+			return nil
+		},
+	}
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lsherman98/yt-rss-cli/config"
+	"github.com/lsherman98/yt-rss-cli/history"
+	"github.com/lsherman98/yt-rss-cli/ui"
+	"github.com/lsherman98/yt-rss-cli/updater"
+	"github.com/spf13/cobra"
+)
+
+type BuildInfo struct {
+	Version string
+	Commit  string
+	Date    string
+}
+
+// Execute runs the command tree and returns the process exit code.
+func Execute(info BuildInfo) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return run(ctx, NewRootCmd(info), os.Stderr)
+}
+
+// run executes root, reports its error on stderr and returns the exit code.
+func run(ctx context.Context, root *cobra.Command, stderr io.Writer) int {
+	if err := root.ExecuteContext(ctx); err != nil {
+		var exitErr *ExitError
+		if !errors.As(err, &exitErr) || exitErr.Err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			if hint := errorHint(err); hint != "" {
+				fmt.Fprintln(stderr, hint)
+			}
+		}
+		return exitCode(err)
+	}
+	return ExitOK
+}
+
+func NewRootCmd(info BuildInfo) *cobra.Command {
+	s := &session{}
+
+	root := &cobra.Command{
+		Use:   "ytrss",
+		Short: "Turn YouTube videos into podcast episodes on ytrss.xyz",
+		Long: "ytrss manages your ytrss.xyz podcasts.\n\n" +
+			"Run without arguments to start the interactive interface, or use one of\n" +
+			"the subcommands below from scripts, cron jobs and CI.",
+		Version:       fmt.Sprintf("%s (commit %s, built %s)", info.Version, info.Commit, info.Date),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          usageArgs(cobra.NoArgs),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateFlags(cmd); err != nil {
+				return err
+			}
+			return s.init(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTUI(info.Version, s)
+		},
+	}
+
+	s.addFlags(root)
+
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &ExitError{Code: ExitUsage, Err: err}
+	})
+
+	root.AddCommand(
+		newAddCmd(),
+		newPodcastsCmd(),
+		newItemsCmd(),
+		newUsageCmd(),
+		newAuthCmd(s),
+		newProfilesCmd(s),
+		newConfigCmd(s),
+		newWatchCmd(),
+		newDevCmd(),
+	)
+
+	return root
+}
+
+func runTUI(version string, s *session) error {
+	if checkForUpdate(version, s.settings.Updates.Mode) {
+		return nil
+	}
+
+	ui.SetTheme(ui.Theme{
+		Accent:  s.settings.Theme.Accent,
+		Muted:   s.settings.Theme.Muted,
+		Error:   s.settings.Theme.Error,
+		Success: s.settings.Theme.Success,
+	})
+	uiSettings := s.settings.UI
+	opts := []ui.Option{
+		ui.WithProfiles(s),
+		ui.WithPollInterval(uiSettings.PollInterval.Value()),
+		ui.WithColumnWidths(uiSettings.TitleWidth, uiSettings.StatusWidth, uiSettings.CreatedWidth),
+	}
+	if hist, err := history.Load(); err == nil {
+		opts = append(opts, ui.WithHistory(hist))
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: duplicate checks will only use the server: reading history: %v\n", err)
+	}
+	if recent, err := history.LoadRecent(); err == nil {
+		opts = append(opts, ui.WithRecents(recent))
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: recently used podcasts won't be remembered: %v\n", err)
+	}
+	model := ui.InitialModel(s.client, opts...)
+
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("uh oh, there was an error: %w", err)
+	}
+	return nil
+}
+
+// checkForUpdate applies the updates.mode setting and reports whether the
+// binary was replaced, in which case the user must restart it.
+func checkForUpdate(version, mode string) bool {
+	switch mode {
+	case config.UpdateOff:
+		return false
+	case config.UpdateNotify:
+		if version == "dev" {
+			return false
+		}
+		latest, newer, err := updater.CheckForUpdate(version)
+		if err == nil && newer {
+			fmt.Printf("🎉 New version available: %s (current: %s)\n", latest.Version(), version)
+			fmt.Println("Run `ytrss config set updates.mode auto` to install updates automatically.")
+		}
+		return false
+	}
+
+	updated, err := updater.CheckAndUpdate(version)
+	if err != nil {
+		fmt.Printf("⚠️  Update check failed: %v\n", err)
+		fmt.Println("Continuing with current version...")
+	}
+	return updated
+}
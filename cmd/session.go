@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/config"
+	"github.com/lsherman98/yt-rss-cli/credentials"
+	"github.com/spf13/cobra"
+)
+
+// session holds the global flags and the loaded config, and builds the API
+// client for the active profile.
+type session struct {
+	profileFlag    string
+	baseURL        string
+	allowInsecure  bool
+	apiKeyFile     string
+	requestTimeout time.Duration
+	connectTimeout time.Duration
+	retries        int
+	retryAdd       bool
+
+	// cfg is the config file as written; settings are the effective values
+	// after applying defaults and environment variables.
+	cfg      *config.Config
+	settings *config.Config
+	profile  string
+	client   *api.APIClient
+}
+
+func (s *session) addFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&s.profileFlag, "profile", "",
+		"profile to use (default $"+config.EnvProfile+" or the active profile)")
+	flags.StringVar(&s.baseURL, "base-url", "",
+		"API base URL (default $"+config.EnvBaseURL+", the profile's base_url or "+api.DefaultBaseURL+")")
+	flags.BoolVar(&s.allowInsecure, "allow-insecure-http", false,
+		"allow sending the API key over plain HTTP to hosts other than localhost")
+	flags.StringVar(&s.apiKeyFile, "api-key-file", "",
+		"read the API key from this file instead of the environment or keyring")
+	flags.DurationVar(&s.requestTimeout, "request-timeout", api.DefaultTimeout,
+		"maximum time for a single API request (0 disables)")
+	flags.DurationVar(&s.connectTimeout, "connect-timeout", api.DefaultConnectTimeout,
+		"maximum time to connect to the API (0 disables)")
+	flags.IntVar(&s.retries, "retries", api.DefaultRetryPolicy.MaxAttempts-1,
+		"how many times to retry requests that fail with a transient error")
+	flags.BoolVar(&s.retryAdd, "retry-add", false,
+		"also retry adding URLs; requests carry an idempotency key so the server can drop duplicates")
+}
+
+// init loads the config and activates the profile selected by the flags.
+func (s *session) init(cmd *cobra.Command) error {
+	if err := s.loadConfig(); err != nil {
+		return err
+	}
+	s.applySettings(cmd)
+	return s.use(s.profile)
+}
+
+func (s *session) loadConfig() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	settings, err := cfg.Resolve()
+	if err != nil {
+		return err
+	}
+	s.cfg = cfg
+	s.settings = settings
+	s.profile = cfg.ResolveProfile(s.profileFlag)
+	return nil
+}
+
+// applySettings fills in the network flags the user did not pass from the
+// config file and environment.
+func (s *session) applySettings(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	network := s.settings.Network
+	if !flags.Changed("request-timeout") {
+		s.requestTimeout = network.RequestTimeout.Value()
+	}
+	if !flags.Changed("connect-timeout") {
+		s.connectTimeout = network.ConnectTimeout.Value()
+	}
+	if !flags.Changed("retries") {
+		s.retries = *network.Retries
+	}
+	if !flags.Changed("retry-add") {
+		s.retryAdd = *network.RetryAdd
+	}
+}
+
+// use builds the API client for the named profile and makes it the default.
+func (s *session) use(name string) error {
+	profile, ok := s.cfg.Profile(name)
+	if !ok {
+		return usageError("profile %q does not exist, create it with `ytrss profiles add %s`", name, name)
+	}
+
+	baseURL, allowInsecure := s.resolveBaseURL(profile)
+	if err := api.ValidateBaseURL(baseURL, allowInsecure); err != nil {
+		return &ExitError{Code: ExitUsage, Err: err}
+	}
+
+	if s.apiKeyFile != "" {
+		if _, err := (credentials.File{Path: s.apiKeyFile, Required: true}).Get(); err != nil {
+			return usageError("--api-key-file: %v", err)
+		}
+	}
+
+	retryPolicy := api.DefaultRetryPolicy
+	retryPolicy.MaxAttempts = s.retries + 1
+	retryPolicy.RetryPOST = s.retryAdd
+
+	s.client = api.NewAPIClient(baseURL,
+		api.WithAllowInsecureHTTP(allowInsecure),
+		api.WithTimeout(s.requestTimeout),
+		api.WithConnectTimeout(s.connectTimeout),
+		api.WithRetryPolicy(retryPolicy),
+		api.WithCredentials(credentials.Default(credentials.Options{
+			APIKeyFile: s.apiKeyFile,
+			Profile:    name,
+		})),
+	)
+	api.SetDefaultClient(s.client)
+	s.profile = name
+	return nil
+}
+
+// resolveBaseURL picks the API endpoint: --base-url, then YTRSS_BASE_URL,
+// then the profile's base_url, then the public server. Insecure HTTP is
+// allowed by the flag or the environment variable for any URL, and by the
+// profile only for the profile's own base_url. With insecure HTTP allowed
+// and no URL configured, the public server is reached over plain HTTP as
+// it was before HTTPS became the default.
+func (s *session) resolveBaseURL(profile config.Profile) (string, bool) {
+	allowInsecure := s.allowInsecure
+	if v, err := strconv.ParseBool(os.Getenv(config.EnvAllowInsecureHTTP)); err == nil && v {
+		allowInsecure = true
+	}
+
+	switch {
+	case s.baseURL != "":
+		return s.baseURL, allowInsecure
+	case os.Getenv(config.EnvBaseURL) != "":
+		return os.Getenv(config.EnvBaseURL), allowInsecure
+	case profile.BaseURL != "":
+		return profile.BaseURL, allowInsecure || profile.AllowInsecureHTTP
+	case allowInsecure:
+		return api.InsecureDefaultBaseURL, true
+	}
+	return api.DefaultBaseURL, false
+}
+
+// The methods below let the TUI list and switch profiles.
+
+func (s *session) Names() []string {
+	return s.cfg.ProfileNames()
+}
+
+func (s *session) Active() string {
+	return s.profile
+}
+
+// Switch activates the profile, remembers it as the active profile for
+// future runs and returns its client.
+func (s *session) Switch(name string) (api.Service, error) {
+	if err := s.use(name); err != nil {
+		return nil, err
+	}
+	s.cfg.ActiveProfile = name
+	if err := config.Save(s.cfg); err != nil {
+		return nil, fmt.Errorf("saving active profile: %w", err)
+	}
+	return s.client, nil
+}
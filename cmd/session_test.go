@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/config"
+	"github.com/spf13/cobra"
+)
+
+func TestResolveBaseURL(t *testing.T) {
+	insecureProfile := config.Profile{BaseURL: "http://nas.lan:8090/api/v1", AllowInsecureHTTP: true}
+
+	tests := []struct {
+		name         string
+		flag         string
+		env          string
+		allowFlag    bool
+		allowEnv     string
+		profile      config.Profile
+		wantURL      string
+		wantInsecure bool
+	}{
+		{name: "default", wantURL: api.DefaultBaseURL},
+		{name: "profile", profile: insecureProfile, wantURL: insecureProfile.BaseURL, wantInsecure: true},
+		{name: "flag overrides profile", flag: "http://other.lan/api/v1", profile: insecureProfile, wantURL: "http://other.lan/api/v1"},
+		{name: "env overrides profile", env: "http://other.lan/api/v1", profile: insecureProfile, wantURL: "http://other.lan/api/v1"},
+		{name: "flag allows insecure", flag: "http://other.lan/api/v1", allowFlag: true, wantURL: "http://other.lan/api/v1", wantInsecure: true},
+		{name: "env allows insecure", env: "http://other.lan/api/v1", allowEnv: "true", wantURL: "http://other.lan/api/v1", wantInsecure: true},
+		{name: "insecure profile without url", profile: config.Profile{AllowInsecureHTTP: true}, wantURL: api.DefaultBaseURL},
+		{name: "insecure default", allowFlag: true, wantURL: api.InsecureDefaultBaseURL, wantInsecure: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(config.EnvBaseURL, tt.env)
+			t.Setenv(config.EnvAllowInsecureHTTP, tt.allowEnv)
+			s := &session{baseURL: tt.flag, allowInsecure: tt.allowFlag}
+
+			url, insecure := s.resolveBaseURL(tt.profile)
+			if url != tt.wantURL || insecure != tt.wantInsecure {
+				t.Errorf("resolveBaseURL() = %q, %v; want %q, %v", url, insecure, tt.wantURL, tt.wantInsecure)
+			}
+		})
+	}
+}
+
+func TestApplySettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("[network]\nretries = 2\nrequest_timeout = \"1m\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.EnvConfig, path)
+	t.Setenv("YTRSS_NETWORK_REQUEST_TIMEOUT", "")
+
+	tests := []struct {
+		name        string
+		args        []string
+		env         string
+		wantRetries int
+	}{
+		{name: "file", wantRetries: 2},
+		{name: "env over file", env: "5", wantRetries: 5},
+		{name: "flag over env", args: []string{"--retries", "1"}, env: "5", wantRetries: 1},
+		{name: "explicit zero flag", args: []string{"--retries", "0"}, env: "5", wantRetries: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("YTRSS_NETWORK_RETRIES", tt.env)
+			s := &session{}
+			cmd := &cobra.Command{Use: "ytrss"}
+			s.addFlags(cmd)
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.loadConfig(); err != nil {
+				t.Fatal(err)
+			}
+			s.applySettings(cmd)
+
+			if s.retries != tt.wantRetries || s.requestTimeout != time.Minute {
+				t.Errorf("retries %d, request timeout %v; want %d and 1m", s.retries, s.requestTimeout, tt.wantRetries)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/output"
+	"github.com/spf13/cobra"
+)
+
+func newUsageCmd() *cobra.Command {
+	var opts output.Options
+
+	usageCmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Show storage usage for your account",
+		Args:  usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutput(opts); err != nil {
+				return err
+			}
+
+			usage, err := api.GetUsage(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			return output.Object(cmd.OutOrStdout(), opts, *usage, usageColumns)
+		},
+	}
+
+	addOutputFlags(usageCmd, &opts)
+	return usageCmd
+}
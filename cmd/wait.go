@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+)
+
+// submission tracks one URL passed to `ytrss add` from the moment it is
+// submitted until its item reaches a terminal status.
+type submission struct {
+	URL         string
+	Item        api.Item
+	Err         error
+	SubmittedAt time.Time
+}
+
+// done reports whether the submission failed or its item reached SUCCESS
+// or ERROR. An empty or unknown status is still pending.
+func (s *submission) done() bool {
+	if s.Err != nil {
+		return true
+	}
+	switch s.Item.Status {
+	case api.StatusSuccess, api.StatusError:
+		return true
+	}
+	return false
+}
+
+// waitForItems polls the podcast's items every interval until every
+// submission is done or ctx is done.
+func waitForItems(ctx context.Context, podcastID string, subs []*submission, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if allDone(subs) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		items, err := api.GetPodcastItems(ctx, podcastID)
+		if err != nil {
+			return err
+		}
+
+		claimed := make(map[string]bool)
+		for _, sub := range subs {
+			if sub.Err == nil && sub.Item.Created != "" {
+				claimed[itemKey(sub.Item)] = true
+			}
+		}
+		for _, sub := range subs {
+			if sub.done() {
+				continue
+			}
+			if item, ok := matchItem(items, sub, claimed); ok {
+				sub.Item = item
+				claimed[itemKey(item)] = true
+			}
+		}
+	}
+}
+
+func allDone(subs []*submission) bool {
+	for _, sub := range subs {
+		if !sub.done() {
+			return false
+		}
+	}
+	return true
+}
+
+func itemKey(item api.Item) string {
+	if item.ID != "" {
+		return item.ID
+	}
+	return item.Created
+}
+
+// matchItem finds the item created for a submission. The API does not
+// always return an item ID, so fall back to the creation timestamp and then
+// to the oldest unclaimed item created since the URL was submitted.
+func matchItem(items []api.Item, sub *submission, claimed map[string]bool) (api.Item, bool) {
+	if sub.Item.ID != "" {
+		for _, item := range items {
+			if item.ID == sub.Item.ID {
+				return item, true
+			}
+		}
+		return api.Item{}, false
+	}
+
+	if sub.Item.Created != "" {
+		for _, item := range items {
+			if item.Created == sub.Item.Created {
+				return item, true
+			}
+		}
+	}
+
+	// Allow for clock skew between this machine and the server.
+	since := sub.SubmittedAt.Add(-time.Minute)
+	var best api.Item
+	found := false
+	for _, item := range items {
+		created := item.CreatedAt()
+		if created.Before(since) || claimed[itemKey(item)] {
+			continue
+		}
+		if !found || created.Before(best.CreatedAt()) {
+			best = item
+			found = true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/config"
+	"github.com/lsherman98/yt-rss-cli/history"
+	"github.com/lsherman98/yt-rss-cli/output"
+	"github.com/lsherman98/yt-rss-cli/watch"
+	"github.com/lsherman98/yt-rss-cli/youtube"
+	"github.com/spf13/cobra"
+)
+
+func newWatchCmd() *cobra.Command {
+	watchCmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Automatically add new uploads from YouTube channels and playlists",
+		Long: "Subscribe podcasts to YouTube channels and playlists, then run `ytrss watch run`\n" +
+			"to poll their feeds and submit every new upload that passes the filters.\n\n" +
+			"Subscriptions and the videos already handled are kept in watch.json in the\n" +
+			"state directory (~/.local/state/ytrss by default, or $" + watch.EnvState + ").",
+	}
+
+	watchCmd.AddCommand(
+		newWatchAddCmd(),
+		newWatchListCmd(),
+		newWatchRemoveCmd(),
+		newWatchRunCmd(),
+	)
+	return watchCmd
+}
+
+// newWatcher builds a watcher around the default API client that logs to
+// the command's stdout.
+func newWatcher(cmd *cobra.Command) (*watch.Watcher, error) {
+	store, err := watch.Load()
+	if err != nil {
+		return nil, fmt.Errorf("reading watch state: %w", err)
+	}
+	hist, err := history.Load()
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: duplicate checks will only use the server: reading history: %v\n", err)
+		hist = nil
+	}
+
+	logger := log.New(cmd.OutOrStdout(), "", log.LstdFlags)
+	return &watch.Watcher{
+		Service: api.DefaultClient(),
+		Feeds:   youtube.NewFeedClient(),
+		Store:   store,
+		History: hist,
+		Logf:    logger.Printf,
+	}, nil
+}
+
+func newWatchAddCmd() *cobra.Command {
+	var (
+		podcastQuery string
+		filter       watch.Filter
+		minDuration  time.Duration
+		maxDuration  time.Duration
+		backfill     bool
+	)
+
+	addCmd := &cobra.Command{
+		Use:   "add --podcast <podcast> <channel-or-playlist-url>...",
+		Short: "Subscribe a podcast to channels or playlists",
+		Long: "Subscribe a podcast to one or more YouTube channels or playlists.\n\n" +
+			"Only videos uploaded after subscribing are submitted, unless --backfill is\n" +
+			"given, in which case the videos currently in the feed (at most 15) are too.\n" +
+			"The duration and Shorts filters look each new video up on YouTube.",
+		Example: "  ytrss watch add --podcast \"My Talks\" https://www.youtube.com/@talks\n" +
+			"  ytrss watch add -p abc123 --match '(?i)interview' --min-duration 20m --exclude-shorts \\\n" +
+			"    https://www.youtube.com/playlist?list=PLxxxxxxxx",
+		Args: usageArgs(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter.MinDuration = config.Duration(minDuration)
+			filter.MaxDuration = config.Duration(maxDuration)
+			if err := filter.Validate(); err != nil {
+				return usageError("%v", err)
+			}
+
+			lists := make([]youtube.List, len(args))
+			for i, raw := range args {
+				list, err := youtube.ParseList(raw)
+				if err != nil {
+					return usageError("%s: %v", raw, err)
+				}
+				lists[i] = list
+			}
+
+			podcast, err := resolvePodcast(cmd.Context(), podcastQuery)
+			if err != nil {
+				return err
+			}
+			w, err := newWatcher(cmd)
+			if err != nil {
+				return err
+			}
+
+			for i, list := range lists {
+				sub := &watch.Subscription{
+					PodcastID:    podcast.ID,
+					PodcastTitle: podcast.Title,
+					URL:          args[i],
+					Filter:       filter,
+				}
+				if err := w.Subscribe(cmd.Context(), sub, list, backfill); err != nil {
+					return fmt.Errorf("subscribing to %s: %w", args[i], err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "✓ [%s] %s now feeds %s\n", sub.ID, sub.Title, podcast.Title)
+			}
+			return nil
+		},
+	}
+
+	flags := addCmd.Flags()
+	flags.StringVarP(&podcastQuery, "podcast", "p", "", "podcast ID or title (required)")
+	flags.StringVar(&filter.Match, "match", "", "only add videos whose title matches this regular expression")
+	flags.DurationVar(&minDuration, "min-duration", 0, "only add videos at least this long, e.g. 5m")
+	flags.DurationVar(&maxDuration, "max-duration", 0, "only add videos at most this long, e.g. 2h")
+	flags.BoolVar(&filter.ExcludeShorts, "exclude-shorts", false, "skip YouTube Shorts")
+	flags.BoolVar(&backfill, "backfill", false, "also add the videos already in the feed")
+	_ = addCmd.MarkFlagRequired("podcast")
+
+	return addCmd
+}
+
+var subscriptionColumns = []output.Column[*watch.Subscription]{
+	{Name: "id", Value: func(s *watch.Subscription) string { return s.ID }},
+	{Name: "podcast", Value: func(s *watch.Subscription) string { return s.PodcastTitle }},
+	{Name: "source", Value: func(s *watch.Subscription) string { return s.Title }},
+	{Name: "filters", Value: func(s *watch.Subscription) string { return filterText(s.Filter) }},
+	{
+		Name: "last_checked",
+		Value: func(s *watch.Subscription) string {
+			if s.LastChecked.IsZero() {
+				return ""
+			}
+			return s.LastChecked.Format(time.RFC3339)
+		},
+		Pretty: func(s *watch.Subscription) string {
+			if s.LastChecked.IsZero() {
+				return "never"
+			}
+			return s.LastChecked.Local().Format("Jan 2, 2006 3:04 PM")
+		},
+	},
+}
+
+func filterText(f watch.Filter) string {
+	var parts []string
+	if f.Match != "" {
+		parts = append(parts, "match "+f.Match)
+	}
+	if f.MinDuration > 0 {
+		parts = append(parts, ">= "+time.Duration(f.MinDuration).String())
+	}
+	if f.MaxDuration > 0 {
+		parts = append(parts, "<= "+time.Duration(f.MaxDuration).String())
+	}
+	if f.ExcludeShorts {
+		parts = append(parts, "no shorts")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func newWatchListCmd() *cobra.Command {
+	var opts output.Options
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List subscriptions",
+		Args:    usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutput(opts); err != nil {
+				return err
+			}
+			store, err := watch.Load()
+			if err != nil {
+				return fmt.Errorf("reading watch state: %w", err)
+			}
+			return output.List(cmd.OutOrStdout(), opts, store.Subscriptions, subscriptionColumns)
+		},
+	}
+
+	addOutputFlags(listCmd, &opts)
+	return listCmd
+}
+
+func newWatchRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <id>...",
+		Aliases: []string{"rm"},
+		Short:   "Unsubscribe",
+		Args:    usageArgs(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := watch.Load()
+			if err != nil {
+				return fmt.Errorf("reading watch state: %w", err)
+			}
+			for _, id := range args {
+				if _, ok := store.Get(id); !ok {
+					return usageError("no subscription with ID %s, see `ytrss watch list`", id)
+				}
+			}
+			for _, id := range args {
+				store.Remove(id)
+			}
+			if err := store.Save(); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed %d subscription(s).\n", len(args))
+			return nil
+		},
+	}
+}
+
+func newWatchRunCmd() *cobra.Command {
+	var (
+		interval time.Duration
+		once     bool
+	)
+
+	runCmd := &cobra.Command{
+		Use:   "run",
+		Short: "Poll the subscriptions and add new uploads",
+		Long: "Poll every subscription and submit new uploads, then keep polling every\n" +
+			"--interval until interrupted. With --once it polls a single time and exits 1\n" +
+			"if any feed or submission failed, which suits running it from cron.",
+		Args: usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if interval < time.Minute {
+				return usageError("--interval must be at least 1m")
+			}
+			w, err := newWatcher(cmd)
+			if err != nil {
+				return err
+			}
+			if len(w.Store.Subscriptions) == 0 {
+				return usageError("no subscriptions, add one with `ytrss watch add`")
+			}
+
+			if once {
+				if err := w.Poll(cmd.Context()); err != nil {
+					return &ExitError{Code: ExitFailure, Err: err}
+				}
+				return nil
+			}
+
+			fmt.Fprintf(cmd.ErrOrStderr(), "Watching %d subscription(s) every %s. Press Ctrl+C to stop.\n",
+				len(w.Store.Subscriptions), interval)
+			return w.Run(cmd.Context(), interval)
+		},
+	}
+
+	runCmd.Flags().DurationVar(&interval, "interval", 15*time.Minute, "time between polls")
+	runCmd.Flags().BoolVar(&once, "once", false, "poll once and exit")
+	return runCmd
+}
@@ -0,0 +1,276 @@
+// Package config loads and saves the ytrss configuration file,
+// ~/.config/ytrss/config.toml on Linux. See CONFIG.md for the schema.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	DefaultProfile = "default"
+
+	EnvConfig            = "YTRSS_CONFIG"
+	EnvProfile           = "YTRSS_PROFILE"
+	EnvBaseURL           = "YTRSS_BASE_URL"
+	EnvAllowInsecureHTTP = "YTRSS_ALLOW_INSECURE_HTTP"
+)
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Config mirrors the config file. Zero values mean "not set"; Resolve fills
+// them in from the defaults and environment variables.
+type Config struct {
+	ActiveProfile string             `toml:"active_profile,omitempty"`
+	UI            UIConfig           `toml:"ui,omitempty"`
+	Theme         ThemeConfig        `toml:"theme,omitempty"`
+	Updates       UpdatesConfig      `toml:"updates,omitempty"`
+	Network       NetworkConfig      `toml:"network,omitempty"`
+	Profiles      map[string]Profile `toml:"profiles,omitempty"`
+}
+
+type UIConfig struct {
+	PollInterval *Duration `toml:"poll_interval,omitempty"`
+	TitleWidth   int       `toml:"title_width,omitzero"`
+	StatusWidth  int       `toml:"status_width,omitzero"`
+	CreatedWidth int       `toml:"created_width,omitzero"`
+}
+
+type ThemeConfig struct {
+	Accent  string `toml:"accent,omitempty"`
+	Muted   string `toml:"muted,omitempty"`
+	Error   string `toml:"error,omitempty"`
+	Success string `toml:"success,omitempty"`
+}
+
+type UpdatesConfig struct {
+	Mode string `toml:"mode,omitempty"`
+}
+
+// Update modes for UpdatesConfig.Mode.
+const (
+	UpdateAuto   = "auto"
+	UpdateNotify = "notify"
+	UpdateOff    = "off"
+)
+
+type NetworkConfig struct {
+	// Pointers distinguish an explicit 0, which disables the timeout or
+	// retries, from an unset value.
+	RequestTimeout *Duration `toml:"request_timeout,omitempty"`
+	ConnectTimeout *Duration `toml:"connect_timeout,omitempty"`
+	Retries        *int      `toml:"retries,omitempty"`
+	RetryAdd       *bool     `toml:"retry_add,omitempty"`
+}
+
+// Profile holds the settings of one ytrss.xyz account. Its API key is kept
+// by the credentials package, not in the config file.
+type Profile struct {
+	// BaseURL overrides the API endpoint; empty means the public server.
+	BaseURL string `toml:"base_url,omitempty"`
+	// AllowInsecureHTTP permits a plain http:// BaseURL on a host other
+	// than localhost.
+	AllowInsecureHTTP bool `toml:"allow_insecure_http,omitempty"`
+}
+
+// Duration is a time.Duration written as a string such as "3s" or "10m".
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Value returns the duration, treating nil as zero.
+func (d *Duration) Value() time.Duration {
+	if d == nil {
+		return 0
+	}
+	return time.Duration(*d)
+}
+
+// Path returns the location of the config file: $YTRSS_CONFIG if set,
+// otherwise ytrss/config.toml in the user's config directory.
+func Path() (string, error) {
+	if path := os.Getenv(EnvConfig); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ytrss", "config.toml"), nil
+}
+
+// Load reads the config file. A missing file yields an empty Config.
+// Unknown keys and invalid values are reported as errors.
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := LoadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	return cfg, err
+}
+
+func LoadFile(path string) (*Config, error) {
+	cfg := &Config{}
+	meta, err := toml.DecodeFile(path, cfg)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		return nil, fmt.Errorf("reading %s: unknown key(s) %s", path, strings.Join(keys, ", "))
+	}
+
+	for _, key := range Keys {
+		if value := key.get(cfg); value != "" {
+			if err := key.set(&Config{}, value); err != nil {
+				return nil, fmt.Errorf("reading %s: %s: %w", path, key.Name, err)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// Save writes cfg to the config file, creating its directory if needed.
+func Save(cfg *Config) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "config-*.toml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := toml.NewEncoder(f).Encode(cfg); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Resolve returns the effective settings: every key's default, overridden
+// by the values in c, overridden by the key's environment variable.
+// Profiles and the active profile are copied unchanged.
+func (c *Config) Resolve() (*Config, error) {
+	resolved := &Config{
+		ActiveProfile: c.ActiveProfile,
+		Profiles:      c.Profiles,
+	}
+	for _, key := range Keys {
+		value := key.Default
+		if v := key.get(c); v != "" {
+			value = v
+		}
+		if v, ok := os.LookupEnv(key.EnvVar()); ok && v != "" {
+			value = v
+		}
+		if err := key.set(resolved, value); err != nil {
+			return nil, fmt.Errorf("%s: %w", key.Name, err)
+		}
+	}
+	return resolved, nil
+}
+
+// ResolveProfile picks the profile to use: the --profile flag, then
+// YTRSS_PROFILE, then the configured active profile, then "default".
+func (c *Config) ResolveProfile(flag string) string {
+	switch {
+	case flag != "":
+		return flag
+	case os.Getenv(EnvProfile) != "":
+		return os.Getenv(EnvProfile)
+	case c.ActiveProfile != "":
+		return c.ActiveProfile
+	}
+	return DefaultProfile
+}
+
+// Profile returns the named profile. The default profile always exists,
+// even when it has no entry in the file.
+func (c *Config) Profile(name string) (Profile, bool) {
+	p, ok := c.Profiles[name]
+	if !ok && name == DefaultProfile {
+		return Profile{}, true
+	}
+	return p, ok
+}
+
+// ProfileNames returns every profile name, including the implicit default
+// profile, sorted alphabetically.
+func (c *Config) ProfileNames() []string {
+	names := []string{DefaultProfile}
+	for name := range c.Profiles {
+		if name != DefaultProfile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *Config) SetProfile(name string, p Profile) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]Profile)
+	}
+	c.Profiles[name] = p
+	return nil
+}
+
+func (c *Config) RemoveProfile(name string) error {
+	if _, ok := c.Profiles[name]; !ok {
+		return fmt.Errorf("profile %q does not exist", name)
+	}
+	delete(c.Profiles, name)
+	if c.ActiveProfile == name {
+		c.ActiveProfile = ""
+	}
+	return nil
+}
+
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, - and _", name)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSetGet(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{key: "ui.poll_interval", value: "10s", want: "10s"},
+		{key: "ui.poll_interval", value: "500ms", wantErr: "at least 1s"},
+		{key: "ui.poll_interval", value: "soon", wantErr: "must be a duration"},
+		{key: "ui.title_width", value: "5", want: "5"},
+		{key: "ui.title_width", value: "501", wantErr: "from 5 to 500"},
+		{key: "theme.accent", value: "#7D56F4", want: "#7D56F4"},
+		{key: "theme.accent", value: "#abc", want: "#abc"},
+		{key: "theme.accent", value: "212", want: "212"},
+		{key: "theme.accent", value: "purple", wantErr: "hex color"},
+		{key: "theme.accent", value: "#12345", wantErr: "hex color"},
+		{key: "theme.accent", value: "2120", wantErr: "hex color"},
+		{key: "updates.mode", value: "notify", want: "notify"},
+		{key: "updates.mode", value: "daily", wantErr: "auto, notify or off"},
+		{key: "network.request_timeout", value: "0s", want: "0s"},
+		{key: "network.retries", value: "0", want: "0"},
+		{key: "network.retries", value: "11", wantErr: "from 0 to 10"},
+		{key: "network.retry_add", value: "true", want: "true"},
+		{key: "network.retry_add", value: "maybe", wantErr: "true or false"},
+		{key: "ui.colour", value: "1", wantErr: `unknown config key "ui.colour"`},
+	}
+	for _, tt := range tests {
+		c := &Config{}
+		err := c.Set(tt.key, tt.value)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Set(%q, %q) error = %v, want %q", tt.key, tt.value, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Set(%q, %q) failed: %v", tt.key, tt.value, err)
+			continue
+		}
+		if got, err := c.Get(tt.key); err != nil || got != tt.want {
+			t.Errorf("Get(%q) = %q, %v; want %q", tt.key, got, err, tt.want)
+		}
+
+		if err := c.Set(tt.key, ""); err != nil {
+			t.Errorf("Set(%q, \"\") failed: %v", tt.key, err)
+		}
+		if got, _ := c.Get(tt.key); got != "" {
+			t.Errorf("Get(%q) after unsetting = %q", tt.key, got)
+		}
+	}
+
+	if _, err := (&Config{}).Get("ui.colour"); err == nil {
+		t.Error("Get accepted an unknown key")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"valid", "active_profile = \"work\"\n[ui]\npoll_interval = \"5s\"\n[theme]\naccent = \"#fff\"\n[profiles.work]\nbase_url = \"http://nas.lan/api/v1\"\nallow_insecure_http = true\n", ""},
+		{"unknown section", "[colors]\naccent = \"#fff\"\n", "unknown key(s) colors"},
+		{"unknown key", "[ui]\npoll_interval = \"5s\"\ntitle_wdith = 40\n", "unknown key(s) ui.title_wdith"},
+		{"invalid color", "[theme]\nerror = \"crimson\"\n", "theme.error: invalid value \"crimson\""},
+		{"invalid duration", "[ui]\npoll_interval = \"100ms\"\n", "ui.poll_interval: invalid value"},
+		{"unparsable duration", "[network]\nrequest_timeout = \"forever\"\n", "request_timeout"},
+		{"syntax", "[ui\n", "reading"},
+	}
+	for _, tt := range tests {
+		cfg, err := LoadFile(writeConfig(t, tt.data))
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: LoadFile failed: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: LoadFile() = %+v, %v; want an error containing %q", tt.name, cfg, err, tt.wantErr)
+		}
+	}
+
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.toml")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LoadFile(missing) error = %v, want ErrNotExist", err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	cfg, err := LoadFile(writeConfig(t, "[ui]\npoll_interval = \"5s\"\ntitle_width = 40\n[network]\nretries = 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("YTRSS_UI_POLL_INTERVAL", "")
+	t.Setenv("YTRSS_UI_TITLE_WIDTH", "80")
+	t.Setenv("YTRSS_THEME_ACCENT", "")
+	t.Setenv("YTRSS_NETWORK_RETRIES", "")
+
+	resolved, err := cfg.Resolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"ui.poll_interval", "5s"},         // file over default
+		{"ui.title_width", "80"},           // environment over file
+		{"theme.accent", "#7D56F4"},        // default
+		{"network.retries", "1"},           // file over default
+		{"network.request_timeout", "30s"}, // default
+	}
+	for _, tt := range tests {
+		if got, _ := resolved.Get(tt.key); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+	if got := resolved.UI.PollInterval.Value(); got != 5*time.Second {
+		t.Errorf("PollInterval = %v, want 5s", got)
+	}
+
+	t.Setenv("YTRSS_THEME_ACCENT", "purple")
+	if _, err := cfg.Resolve(); err == nil || !strings.Contains(err.Error(), "theme.accent") {
+		t.Errorf("Resolve with an invalid environment value error = %v, want one naming theme.accent", err)
+	}
+}
+
+func TestResolveProfile(t *testing.T) {
+	cfg := &Config{ActiveProfile: "work"}
+	tests := []struct {
+		flag, env string
+		cfg       *Config
+		want      string
+	}{
+		{"cli", "env", cfg, "cli"},
+		{"", "env", cfg, "env"},
+		{"", "", cfg, "work"},
+		{"", "", &Config{}, DefaultProfile},
+	}
+	for _, tt := range tests {
+		t.Setenv(EnvProfile, tt.env)
+		if got := tt.cfg.ResolveProfile(tt.flag); got != tt.want {
+			t.Errorf("ResolveProfile(%q) with %s=%q = %q, want %q", tt.flag, EnvProfile, tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	cfg := &Config{ActiveProfile: "work"}
+	if _, ok := cfg.Profile(DefaultProfile); !ok {
+		t.Error("the default profile doesn't exist")
+	}
+	if err := cfg.SetProfile("work", Profile{BaseURL: "http://localhost:8090/api/v1"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"", "my profile", "../work"} {
+		if err := cfg.SetProfile(name, Profile{}); err == nil {
+			t.Errorf("SetProfile(%q) accepted an invalid name", name)
+		}
+	}
+	if got := cfg.ProfileNames(); strings.Join(got, ",") != "default,work" {
+		t.Errorf("ProfileNames = %v", got)
+	}
+
+	if err := cfg.RemoveProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ActiveProfile != "" {
+		t.Errorf("ActiveProfile = %q after removing it", cfg.ActiveProfile)
+	}
+	if err := cfg.RemoveProfile("work"); err == nil {
+		t.Error("RemoveProfile succeeded twice")
+	}
+}
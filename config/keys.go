@@ -0,0 +1,213 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Key is one setting that can be read and written with `ytrss config`.
+type Key struct {
+	Name        string
+	Description string
+	Default     string
+
+	// get returns the value stored in the config, or "" when unset.
+	get func(*Config) string
+	// set parses and validates value and stores it in the config. An
+	// empty value unsets the key.
+	set func(*Config, string) error
+}
+
+// EnvVar returns the environment variable that overrides the key, e.g.
+// YTRSS_UI_POLL_INTERVAL for ui.poll_interval.
+func (k Key) EnvVar() string {
+	return "YTRSS_" + strings.ToUpper(strings.ReplaceAll(k.Name, ".", "_"))
+}
+
+// Keys lists every setting in the order they are documented.
+var Keys = []Key{
+	durationKey("ui.poll_interval", "how often the items table refreshes while episodes are processing", "3s", time.Second,
+		func(c *Config) **Duration { return &c.UI.PollInterval }),
+	intKey("ui.title_width", "width of the title column in the items table", "60",
+		func(c *Config) *int { return &c.UI.TitleWidth }),
+	intKey("ui.status_width", "width of the status column in the items table", "20",
+		func(c *Config) *int { return &c.UI.StatusWidth }),
+	intKey("ui.created_width", "width of the created column in the items table", "30",
+		func(c *Config) *int { return &c.UI.CreatedWidth }),
+	colorKey("theme.accent", "color of titles, highlights and the selected row", "#7D56F4",
+		func(c *Config) *string { return &c.Theme.Accent }),
+	colorKey("theme.muted", "color of help text and secondary information", "#626262",
+		func(c *Config) *string { return &c.Theme.Muted }),
+	colorKey("theme.error", "color of error messages", "#FF0000",
+		func(c *Config) *string { return &c.Theme.Error }),
+	colorKey("theme.success", "color of success messages", "#04B575",
+		func(c *Config) *string { return &c.Theme.Success }),
+	{
+		Name:        "updates.mode",
+		Description: "what to do when a new release is available: auto, notify or off",
+		Default:     UpdateAuto,
+		get:         func(c *Config) string { return c.Updates.Mode },
+		set: func(c *Config, value string) error {
+			switch value {
+			case "", UpdateAuto, UpdateNotify, UpdateOff:
+				c.Updates.Mode = value
+				return nil
+			}
+			return fmt.Errorf("invalid value %q: must be auto, notify or off", value)
+		},
+	},
+	durationKey("network.request_timeout", "maximum time for a single API request (0s disables)", "30s", 0,
+		func(c *Config) **Duration { return &c.Network.RequestTimeout }),
+	durationKey("network.connect_timeout", "maximum time to connect to the API (0s disables)", "10s", 0,
+		func(c *Config) **Duration { return &c.Network.ConnectTimeout }),
+	{
+		Name:        "network.retries",
+		Description: "how many times to retry requests that fail with a transient error",
+		Default:     "3",
+		get: func(c *Config) string {
+			if c.Network.Retries == nil {
+				return ""
+			}
+			return strconv.Itoa(*c.Network.Retries)
+		},
+		set: func(c *Config, value string) error {
+			if value == "" {
+				c.Network.Retries = nil
+				return nil
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 || n > 10 {
+				return fmt.Errorf("invalid value %q: must be a number from 0 to 10", value)
+			}
+			c.Network.Retries = &n
+			return nil
+		},
+	},
+	{
+		Name:        "network.retry_add",
+		Description: "also retry adding URLs, relying on the server to honor idempotency keys",
+		Default:     "false",
+		get: func(c *Config) string {
+			if c.Network.RetryAdd == nil {
+				return ""
+			}
+			return strconv.FormatBool(*c.Network.RetryAdd)
+		},
+		set: func(c *Config, value string) error {
+			if value == "" {
+				c.Network.RetryAdd = nil
+				return nil
+			}
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid value %q: must be true or false", value)
+			}
+			c.Network.RetryAdd = &b
+			return nil
+		},
+	},
+}
+
+// LookupKey finds a key by its dotted name.
+func LookupKey(name string) (Key, bool) {
+	for _, key := range Keys {
+		if key.Name == name {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// Get returns the value stored in the config for key, or "" when unset.
+func (c *Config) Get(name string) (string, error) {
+	key, ok := LookupKey(name)
+	if !ok {
+		return "", fmt.Errorf("unknown config key %q", name)
+	}
+	return key.get(c), nil
+}
+
+// Set validates value and stores it. An empty value resets the key to its
+// default.
+func (c *Config) Set(name, value string) error {
+	key, ok := LookupKey(name)
+	if !ok {
+		return fmt.Errorf("unknown config key %q", name)
+	}
+	return key.set(c, value)
+}
+
+// durationKey describes a duration setting that must be at least min.
+func durationKey(name, description, def string, min time.Duration, field func(*Config) **Duration) Key {
+	return Key{
+		Name:        name,
+		Description: description,
+		Default:     def,
+		get: func(c *Config) string {
+			if d := *field(c); d != nil {
+				return time.Duration(*d).String()
+			}
+			return ""
+		},
+		set: func(c *Config, value string) error {
+			if value == "" {
+				*field(c) = nil
+				return nil
+			}
+			d, err := time.ParseDuration(value)
+			if err != nil || d < min {
+				return fmt.Errorf("invalid value %q: must be a duration of at least %s, such as 3s or 10m", value, min)
+			}
+			v := Duration(d)
+			*field(c) = &v
+			return nil
+		},
+	}
+}
+
+func intKey(name, description, def string, field func(*Config) *int) Key {
+	return Key{
+		Name:        name,
+		Description: description,
+		Default:     def,
+		get: func(c *Config) string {
+			if n := *field(c); n != 0 {
+				return strconv.Itoa(n)
+			}
+			return ""
+		},
+		set: func(c *Config, value string) error {
+			if value == "" {
+				*field(c) = 0
+				return nil
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 5 || n > 500 {
+				return fmt.Errorf("invalid value %q: must be a number from 5 to 500", value)
+			}
+			*field(c) = n
+			return nil
+		},
+	}
+}
+
+var colorPattern = regexp.MustCompile(`^(#[0-9A-Fa-f]{6}|#[0-9A-Fa-f]{3}|[0-9]{1,3})$`)
+
+func colorKey(name, description, def string, field func(*Config) *string) Key {
+	return Key{
+		Name:        name,
+		Description: description,
+		Default:     def,
+		get:         func(c *Config) string { return *field(c) },
+		set: func(c *Config, value string) error {
+			if value != "" && !colorPattern.MatchString(value) {
+				return fmt.Errorf("invalid value %q: must be a hex color like #7D56F4 or an ANSI color number", value)
+			}
+			*field(c) = value
+			return nil
+		},
+	}
+}
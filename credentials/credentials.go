@@ -0,0 +1,162 @@
+// Package credentials locates the ytrss.xyz API key. Keys can come from an
+// explicit file, the YTRSS_API_KEY environment variable, the OS keyring or
+// an encrypted file in the user's config directory, checked in that order.
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	EnvAPIKey     = "YTRSS_API_KEY"
+	EnvPassphrase = "YTRSS_CREDENTIALS_PASSPHRASE"
+
+	serviceName = "ytrss-cli"
+	keyringUser = "api_key"
+)
+
+// ErrNotFound is returned by a Source that holds no API key.
+var ErrNotFound = errors.New("no API key found")
+
+// Source is a place an API key can be read from.
+type Source interface {
+	Name() string
+	Get() (string, error)
+}
+
+// Store is a Source that can also persist and remove a key.
+type Store interface {
+	Source
+	Set(apiKey string) error
+	Clear() error
+}
+
+// Chain checks its sources in order and uses the first key found.
+type Chain struct {
+	Sources []Source
+}
+
+type Options struct {
+	// APIKeyFile, when set, takes precedence over every other source and
+	// must contain a key.
+	APIKeyFile string
+	// Profile selects which stored key the keyring and encrypted file
+	// return. Empty means the default profile.
+	Profile string
+}
+
+// Default returns the standard chain: Options.APIKeyFile, YTRSS_API_KEY,
+// the OS keyring and finally the encrypted credentials file.
+func Default(opts Options) *Chain {
+	var sources []Source
+	if opts.APIKeyFile != "" {
+		sources = append(sources, File{Path: opts.APIKeyFile, Required: true})
+	}
+	user := userForProfile(opts.Profile)
+	sources = append(sources,
+		Env{Var: EnvAPIKey},
+		Keyring{Service: serviceName, User: user},
+		&EncryptedFile{Path: DefaultEncryptedFilePath(), User: user},
+	)
+	return &Chain{Sources: sources}
+}
+
+// userForProfile names the keyring entry of a profile. The default profile
+// keeps the entry used before profiles existed so existing keys still work.
+func userForProfile(profile string) string {
+	if profile == "" || profile == "default" {
+		return keyringUser
+	}
+	return keyringUser + ":" + profile
+}
+
+// DefaultEncryptedFilePath returns the location of the encrypted credentials
+// file, ~/.config/ytrss/credentials.enc on Linux.
+func DefaultEncryptedFilePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "ytrss", "credentials.enc")
+}
+
+// Get returns the first key found and the source it came from. Sources
+// that fail for reasons other than ErrNotFound, such as a keyring without a
+// running Secret Service, are skipped; their error is reported only when no
+// source has a key. A required File that fails stops the search.
+func (c *Chain) Get() (string, Source, error) {
+	var errs []error
+	for _, src := range c.Sources {
+		apiKey, err := src.Get()
+		if err == nil && apiKey != "" {
+			return apiKey, src, nil
+		}
+		if f, ok := src.(File); ok && f.Required {
+			if err == nil {
+				err = ErrNotFound
+			}
+			return "", nil, fmt.Errorf("%s: %w", src.Name(), err)
+		}
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			errs = append(errs, fmt.Errorf("%s: %w", src.Name(), err))
+		}
+	}
+	if len(errs) > 0 {
+		return "", nil, fmt.Errorf("%w (%w)", ErrNotFound, errors.Join(errs...))
+	}
+	return "", nil, ErrNotFound
+}
+
+// Stores returns the writable sources in precedence order.
+func (c *Chain) Stores() []Store {
+	var stores []Store
+	for _, src := range c.Sources {
+		if store, ok := src.(Store); ok {
+			stores = append(stores, store)
+		}
+	}
+	return stores
+}
+
+// Set saves the key in the first store that accepts it, so a keyring that
+// is unavailable on a headless machine falls back to the encrypted file.
+func (c *Chain) Set(apiKey string) (Store, error) {
+	var errs []error
+	for _, store := range c.Stores() {
+		if err := store.Set(apiKey); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", store.Name(), err))
+			continue
+		}
+		return store, nil
+	}
+	if len(errs) == 0 {
+		return nil, errors.New("no writable credential store configured")
+	}
+	return nil, errors.Join(errs...)
+}
+
+// Clear removes the key from every store. Stores that are unavailable or
+// hold no key are not an error.
+func (c *Chain) Clear() error {
+	var errs []error
+	cleared := false
+	for _, store := range c.Stores() {
+		err := store.Clear()
+		switch {
+		case err == nil:
+			cleared = true
+		case !errors.Is(err, ErrNotFound):
+			errs = append(errs, fmt.Errorf("%s: %w", store.Name(), err))
+		}
+	}
+	if cleared {
+		return nil
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return ErrNotFound
+}
@@ -0,0 +1,253 @@
+package credentials
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+// newChain returns the Default chain with an in-memory keyring and the
+// encrypted file in a temporary directory.
+func newChain(t *testing.T, dir string, opts Options) *Chain {
+	t.Helper()
+	t.Setenv(EnvAPIKey, "")
+	t.Setenv(EnvPassphrase, "test passphrase")
+	chain := Default(opts)
+	for _, src := range chain.Sources {
+		if f, ok := src.(*EncryptedFile); ok {
+			f.Path = filepath.Join(dir, "credentials.enc")
+		}
+	}
+	return chain
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEncryptedFileRoundTrip(t *testing.T) {
+	t.Setenv(EnvPassphrase, "correct horse")
+	path := filepath.Join(t.TempDir(), "ytrss", "credentials.enc")
+
+	if err := (&EncryptedFile{Path: path, User: keyringUser}).Set("secret-key"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret-key") {
+		t.Error("the key is stored in plain text")
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("file mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+
+	got, err := (&EncryptedFile{Path: path, User: keyringUser}).Get()
+	if err != nil || got != "secret-key" {
+		t.Errorf("Get() = %q, %v; want %q", got, err, "secret-key")
+	}
+}
+
+func TestEncryptedFileWrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	t.Setenv(EnvPassphrase, "correct horse")
+	if err := (&EncryptedFile{Path: path, User: keyringUser}).Set("secret-key"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(EnvPassphrase, "battery staple")
+	_, err := (&EncryptedFile{Path: path, User: keyringUser}).Get()
+	if err == nil || errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "different passphrase") {
+		t.Errorf("Get() error = %v, want a decryption error", err)
+	}
+}
+
+func TestEncryptedFileCorrupt(t *testing.T) {
+	t.Setenv(EnvPassphrase, "correct horse")
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.enc")
+	if err := (&EncryptedFile{Path: valid, User: keyringUser}).Set("secret-key"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := strings.Replace(string(data), `"ciphertext":"`, `"ciphertext":"AAAA`, 1)
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"garbage", "not json", "corrupt credentials file"},
+		{"version", `{"version": 2}`, "unsupported credentials file version 2"},
+		{"tampered", tampered, "could not decrypt"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name+".enc")
+		writeFile(t, path, tt.data)
+		_, err := (&EncryptedFile{Path: path, User: keyringUser}).Get()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Get() error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	keyring.MockInit()
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "api-key")
+	writeFile(t, keyFile, "  from-file\n")
+
+	chain := newChain(t, dir, Options{APIKeyFile: keyFile})
+	if err := (Keyring{Service: serviceName, User: keyringUser}).Set("from-keyring"); err != nil {
+		t.Fatal(err)
+	}
+	for _, store := range chain.Stores() {
+		if f, ok := store.(*EncryptedFile); ok {
+			if err := f.Set("from-encrypted-file"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	t.Setenv(EnvAPIKey, "from-env")
+
+	steps := []struct {
+		want   string
+		source string
+		next   func()
+	}{
+		{"from-file", "API key file", func() { chain.Sources = chain.Sources[1:] }},
+		{"from-env", "environment variable", func() { t.Setenv(EnvAPIKey, "") }},
+		{"from-keyring", "OS keyring", func() { keyring.Delete(serviceName, keyringUser) }},
+		{"from-encrypted-file", "encrypted file", nil},
+	}
+	for _, step := range steps {
+		got, src, err := chain.Get()
+		if err != nil || got != step.want || !strings.HasPrefix(src.Name(), step.source) {
+			t.Fatalf("Get() = %q from %v, %v; want %q from the %s", got, src, err, step.want, step.source)
+		}
+		if step.next != nil {
+			step.next()
+		}
+	}
+}
+
+func TestChainRequiredFile(t *testing.T) {
+	keyring.MockInit()
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty")
+	writeFile(t, empty, "\n")
+
+	for _, path := range []string{filepath.Join(dir, "missing"), empty, dir} {
+		chain := newChain(t, dir, Options{APIKeyFile: path})
+		t.Setenv(EnvAPIKey, "from-env")
+
+		got, _, err := chain.Get()
+		if err == nil || !strings.Contains(err.Error(), path) {
+			t.Errorf("%s: Get() = %q, %v; want an error naming the file", path, got, err)
+		}
+	}
+}
+
+func TestSetFallsBackWhenKeyringUnavailable(t *testing.T) {
+	keyring.MockInitWithError(errors.New("no Secret Service running"))
+	chain := newChain(t, t.TempDir(), Options{})
+
+	store, err := chain.Set("secret-key")
+	if err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if _, ok := store.(*EncryptedFile); !ok {
+		t.Errorf("Set saved to the %s, want the encrypted file", store.Name())
+	}
+
+	got, src, err := chain.Get()
+	if err != nil || got != "secret-key" || src != store {
+		t.Errorf("Get() = %q from %v, %v; want the key from the encrypted file", got, src, err)
+	}
+}
+
+func TestSetPrefersKeyring(t *testing.T) {
+	keyring.MockInit()
+	chain := newChain(t, t.TempDir(), Options{})
+
+	store, err := chain.Set("secret-key")
+	if err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if _, ok := store.(Keyring); !ok {
+		t.Errorf("Set saved to the %s, want the OS keyring", store.Name())
+	}
+}
+
+func TestClear(t *testing.T) {
+	keyring.MockInit()
+	dir := t.TempDir()
+	chain := newChain(t, dir, Options{})
+	for _, store := range chain.Stores() {
+		if err := store.Set("secret-key"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := chain.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, _, err := chain.Get(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Clear error = %v, want ErrNotFound", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "credentials.enc")); !os.IsNotExist(err) {
+		t.Errorf("the empty encrypted file was not removed: %v", err)
+	}
+	if err := chain.Clear(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Clear error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestProfilesKeepSeparateKeys(t *testing.T) {
+	for _, unavailable := range []bool{false, true} {
+		if unavailable {
+			keyring.MockInitWithError(errors.New("no Secret Service running"))
+		} else {
+			keyring.MockInit()
+		}
+		dir := t.TempDir()
+		chains := map[string]*Chain{
+			"default": newChain(t, dir, Options{}),
+			"work":    newChain(t, dir, Options{Profile: "work"}),
+			"staging": newChain(t, dir, Options{Profile: "staging"}),
+		}
+		for name, chain := range chains {
+			if _, err := chain.Set("key-" + name); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for name, chain := range chains {
+			if got, _, err := chain.Get(); err != nil || got != "key-"+name {
+				t.Errorf("keyring unavailable %v: profile %s Get() = %q, %v", unavailable, name, got, err)
+			}
+		}
+
+		if err := chains["work"].Clear(); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := chains["work"].Get(); !errors.Is(err, ErrNotFound) {
+			t.Errorf("keyring unavailable %v: work key survived Clear: %v", unavailable, err)
+		}
+		if got, _, err := chains["default"].Get(); err != nil || got != "key-default" {
+			t.Errorf("keyring unavailable %v: clearing work changed the default key: %q, %v", unavailable, got, err)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+)
+
+const pbkdf2Iterations = 210_000
+
+// EncryptedFile stores keys in a file encrypted with AES-256-GCM, for
+// machines without a usable OS keyring. The encryption key is derived from
+// YTRSS_CREDENTIALS_PASSPHRASE when set. Otherwise it is derived from the
+// machine ID and user name, which keeps the key out of plain sight and
+// useless when copied to another machine but is no defense against other
+// processes running as the same user.
+type EncryptedFile struct {
+	Path string
+	User string
+
+	mu      sync.Mutex
+	keySalt []byte
+	key     []byte
+}
+
+type encryptedPayload struct {
+	Version    int    `json:"version"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (f *EncryptedFile) Name() string {
+	return "encrypted file " + f.Path
+}
+
+func (f *EncryptedFile) Get() (string, error) {
+	entries, err := f.load()
+	if err != nil {
+		return "", err
+	}
+	apiKey, ok := entries[f.User]
+	if !ok || apiKey == "" {
+		return "", ErrNotFound
+	}
+	return apiKey, nil
+}
+
+func (f *EncryptedFile) Set(apiKey string) error {
+	entries, err := f.load()
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if entries == nil {
+		entries = make(map[string]string)
+	}
+	entries[f.User] = apiKey
+	return f.save(entries)
+}
+
+func (f *EncryptedFile) Clear() error {
+	entries, err := f.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := entries[f.User]; !ok {
+		return ErrNotFound
+	}
+	delete(entries, f.User)
+	if len(entries) == 0 {
+		return os.Remove(f.Path)
+	}
+	return f.save(entries)
+}
+
+func (f *EncryptedFile) load() (map[string]string, error) {
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var payload encryptedPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("corrupt credentials file: %w", err)
+	}
+	if payload.Version != 1 {
+		return nil, fmt.Errorf("unsupported credentials file version %d", payload.Version)
+	}
+
+	gcm, err := f.cipher(payload.Salt)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, payload.Nonce, payload.Ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("could not decrypt credentials file, was it created with a different passphrase?")
+	}
+
+	var entries map[string]string
+	if err := json.Unmarshal(plaintext, &entries); err != nil {
+		return nil, fmt.Errorf("corrupt credentials file: %w", err)
+	}
+	return entries, nil
+}
+
+func (f *EncryptedFile) save(entries map[string]string) error {
+	plaintext, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	gcm, err := f.cipher(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(encryptedPayload{
+		Version:    1,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0o700); err != nil {
+		return err
+	}
+	tmp := f.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.Path)
+}
+
+// cipher derives the AES key for salt. Key derivation is deliberately slow,
+// so the last key is cached for the many reads a TUI session makes.
+func (f *EncryptedFile) cipher(salt []byte) (cipher.AEAD, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.key == nil || string(f.keySalt) != string(salt) {
+		key, err := pbkdf2.Key(sha256.New, passphrase(), salt, pbkdf2Iterations, 32)
+		if err != nil {
+			return nil, err
+		}
+		f.key = key
+		f.keySalt = append([]byte(nil), salt...)
+	}
+
+	block, err := aes.NewCipher(f.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func passphrase() string {
+	if p := os.Getenv(EnvPassphrase); p != "" {
+		return p
+	}
+
+	machineID := ""
+	for _, path := range []string{"/etc/machine-id", "/var/lib/dbus/machine-id"} {
+		if data, err := os.ReadFile(path); err == nil {
+			machineID = string(data)
+			break
+		}
+	}
+	if machineID == "" {
+		machineID, _ = os.Hostname()
+	}
+
+	username := ""
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	}
+
+	return "ytrss-cli:" + machineID + ":" + username
+}
@@ -0,0 +1,87 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Env reads the key from an environment variable.
+type Env struct {
+	Var string
+}
+
+func (e Env) Name() string {
+	return "environment variable " + e.Var
+}
+
+func (e Env) Get() (string, error) {
+	apiKey := strings.TrimSpace(os.Getenv(e.Var))
+	if apiKey == "" {
+		return "", ErrNotFound
+	}
+	return apiKey, nil
+}
+
+// File reads the key from a plain text file, such as a mounted container
+// secret. Surrounding whitespace is ignored.
+type File struct {
+	Path string
+	// Required makes a missing, unreadable or empty file an error rather
+	// than a reason to try the next source. It is set for a file the user
+	// named explicitly.
+	Required bool
+}
+
+func (f File) Name() string {
+	return "API key file " + f.Path
+}
+
+func (f File) Get() (string, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return "", err
+	}
+	apiKey := strings.TrimSpace(string(data))
+	if apiKey == "" {
+		if f.Required {
+			return "", fmt.Errorf("%s is empty", f.Path)
+		}
+		return "", ErrNotFound
+	}
+	return apiKey, nil
+}
+
+// Keyring stores the key in the OS keyring: Keychain on macOS, the Secret
+// Service on Linux and the Credential Manager on Windows.
+type Keyring struct {
+	Service string
+	User    string
+}
+
+func (k Keyring) Name() string {
+	return "OS keyring"
+}
+
+func (k Keyring) Get() (string, error) {
+	apiKey, err := keyring.Get(k.Service, k.User)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	}
+	return apiKey, err
+}
+
+func (k Keyring) Set(apiKey string) error {
+	return keyring.Set(k.Service, k.User, apiKey)
+}
+
+func (k Keyring) Clear() error {
+	err := keyring.Delete(k.Service, k.User)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,322 @@
+// Package history remembers which videos were submitted to which podcast,
+// so re-adding a video can be caught even when the server's item list
+// doesn't say where an episode came from.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/youtube"
+)
+
+// EnvHistory overrides the location of the history file.
+const EnvHistory = "YTRSS_HISTORY"
+
+// Entry is one submitted video. ItemIDs are the items created by each
+// submission, oldest first, and Retries counts how often the video was
+// resubmitted after it failed to process.
+type Entry struct {
+	URL     string    `json:"url"`
+	AddedAt time.Time `json:"added_at"`
+	ItemIDs []string  `json:"item_ids,omitempty"`
+	Retries int       `json:"retries,omitempty"`
+}
+
+// UnmarshalJSON also reads the single item_id written by older versions.
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	type entry Entry
+	var v struct {
+		entry
+		ItemID string `json:"item_id"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*e = Entry(v.entry)
+	if v.ItemID != "" && !e.hasItem(v.ItemID) {
+		e.ItemIDs = append([]string{v.ItemID}, e.ItemIDs...)
+	}
+	return nil
+}
+
+func (e Entry) hasItem(itemID string) bool {
+	return slices.Contains(e.ItemIDs, itemID)
+}
+
+// Store is the history file: video IDs by podcast ID.
+type Store struct {
+	path string
+
+	mu       sync.Mutex
+	podcasts map[string]map[string]Entry
+}
+
+// StateDir returns the directory for files ytrss maintains itself:
+// ytrss under $XDG_STATE_HOME, which defaults to ~/.local/state.
+func StateDir() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "ytrss"), nil
+}
+
+// Path returns the history file location: $YTRSS_HISTORY, or history.json
+// in StateDir.
+func Path() (string, error) {
+	if path := os.Getenv(EnvHistory); path != "" {
+		return path, nil
+	}
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.json"), nil
+}
+
+// Load opens the history file at Path.
+func Load() (*Store, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return Open(path)
+}
+
+// Open reads the history file at path. A missing file yields an empty
+// store that is created on the first Record.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, podcasts: make(map[string]map[string]Entry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.podcasts); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Lookup returns the entry for a video previously submitted to a podcast.
+func (s *Store) Lookup(podcastID, videoID string) (Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.podcasts[podcastID][videoID]
+	return entry, ok
+}
+
+// Record remembers that url was submitted to a podcast, creating the item
+// with itemID, and saves the file. URLs that aren't YouTube video links are
+// ignored.
+func (s *Store) Record(podcastID, url, itemID string, at time.Time) error {
+	_, err := s.record(podcastID, url, itemID, at, false)
+	return err
+}
+
+// RecordRetry is Record for a video resubmitted after it failed. It returns
+// how many times the video has now been retried.
+func (s *Store) RecordRetry(podcastID, url, itemID string, at time.Time) (int, error) {
+	entry, err := s.record(podcastID, url, itemID, at, true)
+	return entry.Retries, err
+}
+
+func (s *Store) record(podcastID, url, itemID string, at time.Time, retry bool) (Entry, error) {
+	video, err := youtube.Parse(url)
+	if err != nil {
+		return Entry{}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.podcasts[podcastID] == nil {
+		s.podcasts[podcastID] = make(map[string]Entry)
+	}
+	prev := s.podcasts[podcastID][video.ID]
+	entry := Entry{URL: url, AddedAt: at.UTC(), ItemIDs: prev.ItemIDs, Retries: prev.Retries}
+	if itemID != "" && !entry.hasItem(itemID) {
+		entry.ItemIDs = append(slices.Clip(entry.ItemIDs), itemID)
+	}
+	if retry {
+		entry.Retries++
+	}
+	s.podcasts[podcastID][video.ID] = entry
+	return entry, s.save()
+}
+
+// Forget drops itemID from a podcast's history after the item was deleted.
+// Once no item of a video is left, the video is removed, so adding it again
+// isn't reported as a duplicate.
+func (s *Store) Forget(podcastID, itemID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for videoID, entry := range s.podcasts[podcastID] {
+		if !entry.hasItem(itemID) {
+			continue
+		}
+		entry.ItemIDs = slices.DeleteFunc(slices.Clone(entry.ItemIDs), func(id string) bool { return id == itemID })
+		if len(entry.ItemIDs) == 0 {
+			delete(s.podcasts[podcastID], videoID)
+		} else {
+			s.podcasts[podcastID][videoID] = entry
+		}
+		return s.save()
+	}
+	return nil
+}
+
+// Retries returns how many times the video in url was resubmitted to a
+// podcast.
+func (s *Store) Retries(podcastID, url string) int {
+	video, err := youtube.Parse(url)
+	if err != nil {
+		return 0
+	}
+	entry, _ := s.Lookup(podcastID, video.ID)
+	return entry.Retries
+}
+
+// SourceURL returns the video an item was created from: the URL reported
+// by the API, or else the one recorded when the item was submitted. store
+// may be nil.
+func SourceURL(store *Store, podcastID string, item api.Item) string {
+	if item.URL != "" || store == nil || item.ID == "" {
+		return item.URL
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	for _, entry := range store.podcasts[podcastID] {
+		if entry.hasItem(item.ID) {
+			return entry.URL
+		}
+	}
+	return ""
+}
+
+func (s *Store) save() error {
+	return writeJSON(s.path, s.podcasts)
+}
+
+// writeJSON replaces the file at path with v, atomically so that a crash
+// never leaves it half written.
+func writeJSON(path string, v any) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Duplicate describes an earlier submission of the same video.
+type Duplicate struct {
+	URL     string
+	AddedAt time.Time
+}
+
+// FindDuplicate reports whether the video in url is already in the podcast.
+// The podcast's items are checked first; an item that failed to process
+// doesn't count, since adding the video again is the way to retry it. When
+// no item mentions the video, the local history is consulted. store may be
+// nil.
+//
+// Items only name their video when the server returns their url. When it
+// doesn't, only videos added from this machine are found.
+func FindDuplicate(items []api.Item, store *Store, podcastID, url string) (Duplicate, bool) {
+	video, err := youtube.Parse(url)
+	if err != nil {
+		return Duplicate{}, false
+	}
+
+	failed := false
+	for _, item := range items {
+		v, err := youtube.Parse(item.URL)
+		if err != nil || v.ID != video.ID {
+			continue
+		}
+		if item.Status == api.StatusError {
+			failed = true
+			continue
+		}
+		return Duplicate{URL: item.URL, AddedAt: item.CreatedAt()}, true
+	}
+	if failed || store == nil {
+		return Duplicate{}, false
+	}
+
+	if entry, ok := store.Lookup(podcastID, video.ID); ok {
+		return Duplicate{URL: entry.URL, AddedAt: entry.AddedAt}, true
+	}
+	return Duplicate{}, false
+}
+
+// Retry is a failed item and the video to resubmit for it. URL is empty
+// when the source of the item is unknown.
+type Retry struct {
+	Item api.Item
+	URL  string
+}
+
+// FailedItems returns the items in ERROR that can be retried. A failure
+// that has since been superseded by a newer item for the same video, for
+// example because it was already retried, is left out. store may be nil.
+func FailedItems(items []api.Item, store *Store, podcastID string) []Retry {
+	urls := make([]string, len(items))
+	keys := make([]string, len(items))
+	latest := make(map[string]int)
+	for i, item := range items {
+		urls[i] = SourceURL(store, podcastID, item)
+		keys[i] = urls[i]
+		if video, err := youtube.Parse(urls[i]); err == nil {
+			keys[i] = video.ID
+		}
+		if keys[i] == "" {
+			continue
+		}
+		if j, ok := latest[keys[i]]; !ok || item.CreatedAt().After(items[j].CreatedAt()) {
+			latest[keys[i]] = i
+		}
+	}
+
+	var failed []Retry
+	for i, item := range items {
+		if item.Status != api.StatusError {
+			continue
+		}
+		if keys[i] != "" && latest[keys[i]] != i {
+			continue
+		}
+		failed = append(failed, Retry{Item: item, URL: urls[i]})
+	}
+	return failed
+}
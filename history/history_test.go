@@ -0,0 +1,199 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+)
+
+func TestRecordPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "history.json")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	if err := store.Record("pod1", "https://youtu.be/dQw4w9WgXcQ?si=x", "item1", at); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := reopened.Lookup("pod1", "dQw4w9WgXcQ")
+	if !ok || !entry.AddedAt.Equal(at) {
+		t.Fatalf("Lookup = %+v, %v; want entry added at %v", entry, ok, at)
+	}
+	if _, ok := reopened.Lookup("pod2", "dQw4w9WgXcQ"); ok {
+		t.Error("entry leaked into another podcast")
+	}
+}
+
+func TestForget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	store.Record("pod1", "https://youtu.be/dQw4w9WgXcQ", "item1", at)
+	store.Record("pod1", "https://youtu.be/9bZkp7q19f0", "item2", at)
+
+	if err := store.Forget("pod1", "item1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Forget("pod1", "unknown"); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := reopened.Lookup("pod1", "dQw4w9WgXcQ"); ok {
+		t.Error("forgotten video is still in the history")
+	}
+	if _, ok := reopened.Lookup("pod1", "9bZkp7q19f0"); !ok {
+		t.Error("another video was forgotten too")
+	}
+}
+
+func TestFindDuplicate(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Record("pod1", "https://www.youtube.com/watch?v=historyOnly", "", time.Now())
+	store.Record("pod1", "https://www.youtube.com/watch?v=failedVideo", "", time.Now())
+
+	items := []api.Item{
+		{Status: api.StatusSuccess, URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", Created: "2026-01-02 03:04:05.000Z"},
+		{Status: api.StatusError, URL: "https://www.youtube.com/watch?v=failedVideo"},
+	}
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://youtu.be/dQw4w9WgXcQ", true},
+		{"https://youtu.be/historyOnly", true},
+		{"https://youtu.be/failedVideo", false},
+		{"https://youtu.be/brandNewVid", false},
+		{"not a url", false},
+	}
+	for _, tt := range tests {
+		if _, got := FindDuplicate(items, store, "pod1", tt.url); got != tt.want {
+			t.Errorf("FindDuplicate(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+
+	dup, _ := FindDuplicate(items, nil, "pod1", "https://youtu.be/dQw4w9WgXcQ")
+	if want := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC); !dup.AddedAt.Equal(want) {
+		t.Errorf("AddedAt = %v, want %v", dup.AddedAt, want)
+	}
+}
+
+func TestFailedItems(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Record("pod1", "https://www.youtube.com/watch?v=noURLInItem", "item3", time.Now())
+
+	items := []api.Item{
+		{ID: "item1", Status: api.StatusError, URL: "https://www.youtube.com/watch?v=retriedVide", Created: "2026-01-01 00:00:00.000Z"},
+		{ID: "item2", Status: api.StatusCreated, URL: "https://www.youtube.com/watch?v=retriedVide", Created: "2026-01-02 00:00:00.000Z"},
+		{ID: "item3", Status: api.StatusError, Created: "2026-01-01 00:00:00.000Z"},
+		{ID: "item4", Status: api.StatusError, Created: "2026-01-01 00:00:00.000Z"},
+		{ID: "item5", Status: api.StatusSuccess, URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+	}
+	got := FailedItems(items, store, "pod1")
+	want := []Retry{
+		{Item: items[2], URL: "https://www.youtube.com/watch?v=noURLInItem"},
+		{Item: items[3]},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("FailedItems = %v, want %v", got, want)
+	}
+
+	retries, err := store.RecordRetry("pod1", "https://youtu.be/noURLInItem", "item6", time.Now())
+	if err != nil || retries != 1 {
+		t.Fatalf("RecordRetry = %d, %v; want 1", retries, err)
+	}
+	if got := store.Retries("pod1", "https://www.youtube.com/watch?v=noURLInItem"); got != 1 {
+		t.Errorf("Retries = %d, want 1", got)
+	}
+
+	// Neither item names its video, so only the history ties the failed
+	// item to its retry.
+	items = append(items, api.Item{ID: "item6", Status: api.StatusCreated, Created: "2026-01-03 00:00:00.000Z"})
+	got = FailedItems(items, store, "pod1")
+	want = []Retry{{Item: items[3]}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("FailedItems after a retry = %v, want %v", got, want)
+	}
+	if url := SourceURL(store, "pod1", items[2]); url != "https://youtu.be/noURLInItem" {
+		t.Errorf("SourceURL of the retried item = %q", url)
+	}
+}
+
+func TestForgetKeepsVideoWithOtherItems(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Record("pod1", "https://youtu.be/dQw4w9WgXcQ", "item1", time.Now())
+	store.RecordRetry("pod1", "https://youtu.be/dQw4w9WgXcQ", "item2", time.Now())
+
+	if err := store.Forget("pod1", "item1"); err != nil {
+		t.Fatal(err)
+	}
+	if entry, ok := store.Lookup("pod1", "dQw4w9WgXcQ"); !ok || fmt.Sprint(entry.ItemIDs) != "[item2]" {
+		t.Errorf("Lookup after forgetting the failed item = %+v, %v; want item2 kept", entry, ok)
+	}
+	store.Forget("pod1", "item2")
+	if _, ok := store.Lookup("pod1", "dQw4w9WgXcQ"); ok {
+		t.Error("video is still in the history after all its items were forgotten")
+	}
+}
+
+func TestOpenReadsSingleItemID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	data := `{"pod1": {"dQw4w9WgXcQ": {"url": "https://youtu.be/dQw4w9WgXcQ", "added_at": "2026-03-04T05:06:07Z", "item_id": "item1"}}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url := SourceURL(store, "pod1", api.Item{ID: "item1"}); url != "https://youtu.be/dQw4w9WgXcQ" {
+		t.Errorf("SourceURL = %q, want the URL recorded with item_id", url)
+	}
+}
+
+func TestRecentPodcasts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recent.json")
+	recent, err := OpenRecent(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"pod1", "pod2", "pod3", "pod4", "pod5", "pod6", "pod2"} {
+		if err := recent.Use(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reopened, err := OpenRecent(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"pod2", "pod6", "pod5", "pod4", "pod3"}
+	if got := reopened.IDs(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("IDs = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,70 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// maxRecent is how many podcasts RecentPodcasts remembers.
+const maxRecent = 5
+
+// RecentPodcasts remembers the podcasts picked most recently in the TUI so
+// they can be offered first next time.
+type RecentPodcasts struct {
+	path string
+
+	mu  sync.Mutex
+	ids []string
+}
+
+// LoadRecent opens recent.json in StateDir.
+func LoadRecent() (*RecentPodcasts, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return nil, err
+	}
+	return OpenRecent(filepath.Join(dir, "recent.json"))
+}
+
+// OpenRecent reads the recent podcasts file at path. A missing file yields
+// an empty list that is created on the first Use.
+func OpenRecent(path string) (*RecentPodcasts, error) {
+	r := &RecentPodcasts{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.ids); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// IDs returns the podcast IDs, most recently used first.
+func (r *RecentPodcasts) IDs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.ids)
+}
+
+// Use moves the podcast to the front of the list and saves the file.
+func (r *RecentPodcasts) Use(podcastID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := []string{podcastID}
+	for _, id := range r.ids {
+		if id != podcastID && len(ids) < maxRecent {
+			ids = append(ids, id)
+		}
+	}
+	r.ids = ids
+	return writeJSON(r.path, r.ids)
+}
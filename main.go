@@ -1,12 +1,9 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
-	tea "github.com/charmbracelet/bubbletea"
-	"github.com/lsherman98/yt-rss-cli/ui"
-	"github.com/lsherman98/yt-rss-cli/updater"
+	"github.com/lsherman98/yt-rss-cli/cmd"
 )
 
 var (
@@ -16,18 +13,9 @@ var (
 )
 
 func main() {
-	updated, err := updater.CheckAndUpdate(version)
-	if err != nil {
-		fmt.Printf("⚠️  Update check failed: %v\n", err)
-		fmt.Println("Continuing with current version...")
-	}
-	if updated {
-		os.Exit(0)
-	}
-
-	p := tea.NewProgram(ui.InitialModel(), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Uh oh, there was an error: %v\n", err)
-		os.Exit(1)
-	}
+	os.Exit(cmd.Execute(cmd.BuildInfo{
+		Version: version,
+		Commit:  commit,
+		Date:    date,
+	}))
 }
@@ -0,0 +1,235 @@
+// Package output renders command results in human and machine readable
+// formats.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+	FormatCSV   Format = "csv"
+	FormatTSV   Format = "tsv"
+)
+
+var Formats = []Format{FormatTable, FormatJSON, FormatYAML, FormatCSV, FormatTSV}
+
+// Options selects how results are written. A non-empty Template takes
+// precedence over Format and is executed once per row.
+type Options struct {
+	Format   Format
+	Template string
+}
+
+func (o Options) Validate() error {
+	if o.Template != "" {
+		_, err := template.New("format").Parse(o.Template)
+		if err != nil {
+			return fmt.Errorf("invalid --format template: %w", err)
+		}
+		return nil
+	}
+	for _, f := range Formats {
+		if o.Format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output format %q (valid: %s)", o.Format, formatList())
+}
+
+// Column describes one field of a tabular rendering. Name is used verbatim as
+// the CSV/TSV header and upper-cased for tables. Pretty, when set, replaces
+// Value in table output only, so machine formats keep raw values.
+type Column[T any] struct {
+	Name   string
+	Value  func(T) string
+	Pretty func(T) string
+}
+
+// List writes rows in the selected format. JSON and YAML output is an array,
+// even when empty.
+func List[T any](w io.Writer, opts Options, rows []T, cols []Column[T]) error {
+	if rows == nil {
+		rows = []T{}
+	}
+
+	if opts.Template != "" {
+		return writeTemplate(w, opts.Template, rows)
+	}
+
+	switch opts.Format {
+	case FormatJSON:
+		return writeJSON(w, rows)
+	case FormatYAML:
+		return writeYAML(w, rows)
+	case FormatCSV:
+		return writeCSV(w, ',', rows, cols)
+	case FormatTSV:
+		return writeTSV(w, rows, cols)
+	case FormatTable, "":
+		return writeTable(w, rows, cols)
+	default:
+		return fmt.Errorf("unknown output format %q", opts.Format)
+	}
+}
+
+// Object writes a single value. JSON and YAML output is an object rather
+// than a one-element array.
+func Object[T any](w io.Writer, opts Options, v T, cols []Column[T]) error {
+	switch {
+	case opts.Template != "":
+		return writeTemplate(w, opts.Template, []T{v})
+	case opts.Format == FormatJSON:
+		return writeJSON(w, v)
+	case opts.Format == FormatYAML:
+		return writeYAML(w, v)
+	default:
+		return List(w, opts, []T{v}, cols)
+	}
+}
+
+func writeTemplate[T any](w io.Writer, text string, rows []T) error {
+	tmpl, err := template.New("format").Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid --format template: %w", err)
+	}
+	for _, row := range rows {
+		if err := tmpl.Execute(w, row); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeYAML(w io.Writer, v any) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func writeCSV[T any](w io.Writer, comma rune, rows []T, cols []Column[T]) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+
+	header := make([]string, len(cols))
+	for i, col := range cols {
+		header[i] = col.Name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(cols))
+		for i, col := range cols {
+			record[i] = col.Value(row)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// flatten replaces tabs and newlines inside values with spaces so a value
+// never spans more than one cell or line.
+var flatten = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+// writeTSV emits one line per row, which keeps the output friendly to cut
+// and awk.
+func writeTSV[T any](w io.Writer, rows []T, cols []Column[T]) error {
+	header := make([]string, len(cols))
+	for i, col := range cols {
+		header[i] = col.Name
+	}
+	if _, err := fmt.Fprintln(w, strings.Join(header, "\t")); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		fields := make([]string, len(cols))
+		for i, col := range cols {
+			fields[i] = flatten.Replace(col.Value(row))
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTable[T any](w io.Writer, rows []T, cols []Column[T]) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := make([]string, len(cols))
+	for i, col := range cols {
+		header[i] = strings.ToUpper(col.Name)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, row := range rows {
+		fields := make([]string, len(cols))
+		for i, col := range cols {
+			if col.Pretty != nil {
+				fields[i] = flatten.Replace(col.Pretty(row))
+			} else {
+				fields[i] = flatten.Replace(col.Value(row))
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(fields, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+func formatList() string {
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
+
+// FormatBytes renders a byte count using binary units, e.g. "1.50 GB".
+func FormatBytes(bytes int) string {
+	const (
+		KB = 1024
+		MB = 1024 * KB
+		GB = 1024 * MB
+	)
+
+	if bytes >= GB {
+		return fmt.Sprintf("%.2f GB", float64(bytes)/float64(GB))
+	} else if bytes >= MB {
+		return fmt.Sprintf("%.2f MB", float64(bytes)/float64(MB))
+	} else if bytes >= KB {
+		return fmt.Sprintf("%.2f KB", float64(bytes)/float64(KB))
+	}
+	return fmt.Sprintf("%d B", bytes)
+}
@@ -0,0 +1,178 @@
+package output
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type row struct {
+	ID    string `json:"id" yaml:"id"`
+	Title string `json:"title" yaml:"title"`
+	Size  int    `json:"size" yaml:"size"`
+}
+
+var cols = []Column[row]{
+	{Name: "id", Value: func(r row) string { return r.ID }},
+	{Name: "title", Value: func(r row) string { return r.Title }},
+	{
+		Name:   "size",
+		Value:  func(r row) string { return strconv.Itoa(r.Size) },
+		Pretty: func(r row) string { return FormatBytes(r.Size) },
+	},
+}
+
+var rows = []row{
+	{ID: "pod001", Title: "Talks", Size: 2048},
+	{ID: "pod002", Title: "Tabs\tand \"quotes\",\nnewlines", Size: 12},
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		rows []row
+		want string
+	}{
+		{
+			name: "table",
+			opts: Options{Format: FormatTable},
+			rows: rows,
+			want: "" +
+				"ID      TITLE                        SIZE\n" +
+				"pod001  Talks                        2.00 KB\n" +
+				"pod002  Tabs and \"quotes\", newlines  12 B\n",
+		},
+		{
+			name: "default is table",
+			rows: rows[:1],
+			want: "" +
+				"ID      TITLE  SIZE\n" +
+				"pod001  Talks  2.00 KB\n",
+		},
+		{
+			name: "json",
+			opts: Options{Format: FormatJSON},
+			rows: rows[:1],
+			want: "[\n  {\n    \"id\": \"pod001\",\n    \"title\": \"Talks\",\n    \"size\": 2048\n  }\n]\n",
+		},
+		{
+			name: "empty json",
+			opts: Options{Format: FormatJSON},
+			want: "[]\n",
+		},
+		{
+			name: "yaml",
+			opts: Options{Format: FormatYAML},
+			rows: rows[:1],
+			want: "- id: pod001\n  title: Talks\n  size: 2048\n",
+		},
+		{
+			name: "empty yaml",
+			opts: Options{Format: FormatYAML},
+			want: "[]\n",
+		},
+		{
+			name: "csv",
+			opts: Options{Format: FormatCSV},
+			rows: rows,
+			want: "" +
+				"id,title,size\n" +
+				"pod001,Talks,2048\n" +
+				"pod002,\"Tabs\tand \"\"quotes\"\",\nnewlines\",12\n",
+		},
+		{
+			name: "tsv",
+			opts: Options{Format: FormatTSV},
+			rows: rows,
+			want: "" +
+				"id\ttitle\tsize\n" +
+				"pod001\tTalks\t2048\n" +
+				"pod002\tTabs and \"quotes\", newlines\t12\n",
+		},
+		{
+			name: "empty tsv keeps the header",
+			opts: Options{Format: FormatTSV},
+			want: "id\ttitle\tsize\n",
+		},
+		{
+			name: "template",
+			opts: Options{Format: FormatJSON, Template: "{{.ID}}={{.Size}}"},
+			rows: rows,
+			want: "pod001=2048\npod002=12\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := List(&b, tt.opts, tt.rows, cols); err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("List output:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObject(t *testing.T) {
+	tests := []struct {
+		opts Options
+		want string
+	}{
+		{Options{Format: FormatJSON}, "{\n  \"id\": \"pod001\",\n  \"title\": \"Talks\",\n  \"size\": 2048\n}\n"},
+		{Options{Format: FormatYAML}, "id: pod001\ntitle: Talks\nsize: 2048\n"},
+		{Options{Format: FormatTSV}, "id\ttitle\tsize\npod001\tTalks\t2048\n"},
+		{Options{Template: "{{.Title}}"}, "Talks\n"},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := Object(&b, tt.opts, rows[0], cols); err != nil {
+			t.Fatalf("Object(%+v) failed: %v", tt.opts, err)
+		}
+		if got := b.String(); got != tt.want {
+			t.Errorf("Object(%+v) output:\n%s\nwant:\n%s", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		opts    Options
+		wantErr string
+	}{
+		{Options{Format: FormatTable}, ""},
+		{Options{Format: FormatCSV}, ""},
+		{Options{Format: "xml"}, `unknown output format "xml" (valid: table, json, yaml, csv, tsv)`},
+		{Options{Format: "xml", Template: "{{.ID}}"}, ""},
+		{Options{Template: "{{.ID"}, "invalid --format template"},
+	}
+	for _, tt := range tests {
+		err := tt.opts.Validate()
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("Validate(%+v) = %v, want nil", tt.opts, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("Validate(%+v) = %v, want error containing %q", tt.opts, err, tt.wantErr)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		bytes int
+		want  string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.00 KB"},
+		{1536, "1.50 KB"},
+		{5 * 1024 * 1024, "5.00 MB"},
+		{3 * 1024 * 1024 * 1024 / 2, "1.50 GB"},
+	}
+	for _, tt := range tests {
+		if got := FormatBytes(tt.bytes); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/history"
+)
+
+type ClipboardMsg struct {
+	What string
+	Err  error
+}
+
+// CopyText puts text on the clipboard; what names it in the confirmation.
+func CopyText(copy func(string) error, what, text string) tea.Cmd {
+	return func() tea.Msg {
+		return ClipboardMsg{What: what, Err: copy(text)}
+	}
+}
+
+// copyToClipboard uses the system clipboard and falls back to an OSC 52
+// escape sequence, which most terminals honour even over SSH.
+func copyToClipboard(text string) error {
+	if err := clipboard.WriteAll(text); err == nil {
+		return nil
+	}
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	}
+	_, err := seq.WriteTo(os.Stderr)
+	return err
+}
+
+// selectedItem returns the item under the table cursor.
+func (m Model) selectedItem() (api.Item, bool) {
+	index := m.ItemsTable.Cursor()
+	if index < 0 || index >= len(m.itemRows) {
+		return api.Item{}, false
+	}
+	return m.itemRows[index], true
+}
+
+// openItemDetail shows the item under the table cursor.
+func (m *Model) openItemDetail() {
+	item, ok := m.selectedItem()
+	if !ok {
+		return
+	}
+	m.detailItem = item
+	m.Message = ""
+	m.Error = ""
+	m.State = ViewItemDetail
+}
+
+// refreshItemDetail picks up status changes for the item being shown.
+func (m *Model) refreshItemDetail() {
+	if m.detailItem.ID == "" {
+		return
+	}
+	for _, item := range m.Items {
+		if item.ID == m.detailItem.ID {
+			m.detailItem = item
+			return
+		}
+	}
+}
+
+func (m *Model) updateItemDetail(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		m.Polling = false
+		m.cancelRequests()
+		return tea.Quit
+	case "esc", "backspace":
+		m.Message = ""
+		m.State = ViewItemsTable
+	case "r":
+		m.State = ViewItemsTable
+		return m.retryItem(m.detailItem)
+	case "c":
+		if m.detailItem.Error == "" {
+			m.Message = "This item has no error to copy"
+			return nil
+		}
+		return CopyText(m.copy, "error", m.detailItem.Error)
+	case "u":
+		url := history.SourceURL(m.history, m.SelectedPodcast.ID, m.detailItem)
+		if url == "" {
+			m.Message = "This item has no source URL"
+			return nil
+		}
+		return CopyText(m.copy, "URL", url)
+	}
+	return nil
+}
+
+// detailLabelWidth fits the longest field label and a space.
+const detailLabelWidth = 13
+
+func (m Model) viewItemDetail() string {
+	item := m.detailItem
+	var s strings.Builder
+
+	title := item.Title
+	if title == "" {
+		title = "(No title)"
+	}
+	s.WriteString(TitleStyle.Render(title))
+	s.WriteString("\n")
+
+	width := m.Width
+	if width <= 0 {
+		width = 80
+	}
+	label := lipgloss.NewStyle().Foreground(AccentColor).Width(detailLabelWidth)
+	value := lipgloss.NewStyle().Width(max(width-detailLabelWidth, 20))
+	field := func(name, text string) {
+		if text == "" {
+			return
+		}
+		s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, label.Render(name), value.Render(text)))
+		s.WriteString("\n")
+	}
+
+	field("Status", statusText(item.Status, ""))
+	created := item.Created
+	if t := item.CreatedAt(); !t.IsZero() {
+		created = t.Local().Format("Jan 2, 2006 3:04:05 PM")
+	}
+	field("Created", created)
+	if item.Published != "" {
+		field("Published", item.PublishedAt().Local().Format("Jan 2, 2006 3:04:05 PM"))
+	}
+	if item.Hidden {
+		field("Hidden", "Yes, left out of the feed")
+	}
+	field("Description", item.Description)
+	field("ID", item.ID)
+	field("URL", history.SourceURL(m.history, m.SelectedPodcast.ID, item))
+	if n := m.retries(item); n > 0 {
+		field("Retries", fmt.Sprint(n))
+	}
+	if item.Error != "" {
+		s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, label.Render("Error"), ErrorStyle.Width(max(width-detailLabelWidth, 20)).Render(item.Error)))
+		s.WriteString("\n")
+	}
+
+	if len(item.Extra) > 0 {
+		s.WriteString("\n")
+		s.WriteString(MutedStyle.Render("Other fields"))
+		s.WriteString("\n")
+		keys := make([]string, 0, len(item.Extra))
+		for k := range item.Extra {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			field(k, extraText(item.Extra[k]))
+		}
+	}
+
+	if m.Message != "" {
+		s.WriteString("\n")
+		s.WriteString(SuccessStyle.Render(m.Message))
+		s.WriteString("\n")
+	}
+	s.WriteString(HelpStyle.Render("r: Retry • c: Copy error • u: Copy URL • Esc: Back • q: Quit"))
+	return s.String()
+}
+
+// statusText labels a status the way the items table does. spinner is
+// shown in front of CREATED.
+func statusText(status, spinner string) string {
+	switch status {
+	case api.StatusCreated:
+		return strings.TrimLeft(spinner+" PROCESSING", " ")
+	case api.StatusError:
+		return "❌ ERROR"
+	case api.StatusSuccess:
+		return "✓ SUCCESS"
+	}
+	return status
+}
+
+func extraText(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,221 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/sahilm/fuzzy"
+)
+
+type sortColumn int
+
+const (
+	sortByCreated sortColumn = iota
+	sortByTitle
+	sortByStatus
+)
+
+// itemFilter narrows and orders the items table. The zero value shows every
+// item, newest first.
+type itemFilter struct {
+	query string
+	// statuses are the statuses to show; none selected shows them all.
+	statuses map[string]bool
+	sortBy   sortColumn
+	// reverse flips the natural order of the column: newest first for
+	// Created, A–Z for Title and failures first for Status.
+	reverse bool
+}
+
+// filterKeys toggle the status filters.
+var filterKeys = map[string]string{
+	"1": api.StatusCreated,
+	"2": api.StatusSuccess,
+	"3": api.StatusError,
+}
+
+var statusNames = map[string]string{
+	api.StatusCreated: "processing",
+	api.StatusSuccess: "success",
+	api.StatusError:   "error",
+}
+
+// statusOrder puts failures first, since they are what people look for.
+var statusOrder = map[string]int{
+	api.StatusError:   0,
+	api.StatusCreated: 1,
+	api.StatusSuccess: 2,
+}
+
+func (f *itemFilter) toggle(status string) {
+	if f.statuses == nil {
+		f.statuses = make(map[string]bool)
+	}
+	f.statuses[status] = !f.statuses[status]
+}
+
+func (f itemFilter) showsStatus(status string) bool {
+	filtered := false
+	for _, on := range f.statuses {
+		filtered = filtered || on
+	}
+	return !filtered || f.statuses[status]
+}
+
+func (f itemFilter) active() bool {
+	return f.query != "" || !f.showsStatus("")
+}
+
+// apply returns the items that pass the filter in display order.
+func (f itemFilter) apply(items []api.Item) []api.Item {
+	var shown []api.Item
+	for _, item := range items {
+		if f.showsStatus(item.Status) {
+			shown = append(shown, item)
+		}
+	}
+	if f.query != "" {
+		titles := make([]string, len(shown))
+		for i, item := range shown {
+			titles[i] = itemTitle(item)
+		}
+		var matched []api.Item
+		for _, match := range fuzzy.Find(f.query, titles) {
+			matched = append(matched, shown[match.Index])
+		}
+		shown = matched
+	}
+
+	sort.SliceStable(shown, func(i, j int) bool {
+		if f.reverse {
+			i, j = j, i
+		}
+		a, b := shown[i], shown[j]
+		switch f.sortBy {
+		case sortByTitle:
+			return strings.ToLower(itemTitle(a)) < strings.ToLower(itemTitle(b))
+		case sortByStatus:
+			if statusOrder[a.Status] != statusOrder[b.Status] {
+				return statusOrder[a.Status] < statusOrder[b.Status]
+			}
+		}
+		return newerThan(a, b)
+	})
+	return shown
+}
+
+// newerThan orders items newest first, with undated items last.
+func newerThan(a, b api.Item) bool {
+	timeA, timeB := a.CreatedAt(), b.CreatedAt()
+	if timeA.IsZero() || timeB.IsZero() {
+		return !timeA.IsZero() && timeB.IsZero()
+	}
+	return timeA.After(timeB)
+}
+
+// itemTitle is the title shown for an item in the table.
+func itemTitle(item api.Item) string {
+	switch {
+	case item.Title != "":
+		return item.Title
+	case item.Status == api.StatusCreated:
+		return "Processing..."
+	}
+	return "(No title)"
+}
+
+// markSorted adds the sort indicator to the header of the sorted column:
+// ↓ when it is in descending order, as Created is by default, and ↑ when
+// ascending.
+func (f itemFilter) markSorted(columns []table.Column) {
+	descending := f.sortBy == sortByCreated
+	if f.reverse {
+		descending = !descending
+	}
+	arrow := " ↑"
+	if descending {
+		arrow = " ↓"
+	}
+	column := map[sortColumn]int{sortByTitle: 0, sortByStatus: 1, sortByCreated: 2}[f.sortBy]
+	columns[column].Title += arrow
+}
+
+// summary describes the active filters, or is empty when there are none.
+func (f itemFilter) summary(shown, total int) string {
+	if !f.active() {
+		return ""
+	}
+	var parts []string
+	parts = append(parts, fmt.Sprintf("%d of %d items", shown, total))
+	if f.query != "" {
+		parts = append(parts, fmt.Sprintf("matching %q", f.query))
+	}
+	var statuses []string
+	for _, key := range []string{"1", "2", "3"} {
+		if status := filterKeys[key]; f.statuses[status] {
+			statuses = append(statuses, statusNames[status])
+		}
+	}
+	if len(statuses) > 0 {
+		parts = append(parts, "status: "+strings.Join(statuses, ", "))
+	}
+	return strings.Join(parts, " • ")
+}
+
+// updateItemsFilter handles the filter and sort keys of the items table. It
+// reports whether the key was one of them.
+func (m *Model) updateItemsFilter(msg tea.KeyMsg) bool {
+	switch key := msg.String(); key {
+	case "/":
+		m.searching = true
+		m.SearchInput.SetValue(m.itemFilter.query)
+		m.SearchInput.CursorEnd()
+		m.SearchInput.Focus()
+	case "1", "2", "3":
+		m.itemFilter.toggle(filterKeys[key])
+	case "o":
+		m.itemFilter.sortBy = (m.itemFilter.sortBy + 1) % 3
+		m.itemFilter.reverse = false
+	case "O":
+		m.itemFilter.reverse = !m.itemFilter.reverse
+	case "esc":
+		m.itemFilter.query = ""
+		m.itemFilter.statuses = nil
+	default:
+		return false
+	}
+	m.buildItemsTable()
+	return true
+}
+
+// updateItemSearch handles keys while the search box has focus. The table
+// is filtered as the query is typed.
+func (m *Model) updateItemSearch(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		m.Polling = false
+		m.cancelRequests()
+		return tea.Quit
+	case "enter":
+		m.searching = false
+		m.SearchInput.Blur()
+		return nil
+	case "esc":
+		m.searching = false
+		m.SearchInput.Blur()
+		m.SearchInput.SetValue("")
+		m.itemFilter.query = ""
+		m.buildItemsTable()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.SearchInput, cmd = m.SearchInput.Update(msg)
+	m.itemFilter.query = strings.TrimSpace(m.SearchInput.Value())
+	m.buildItemsTable()
+	return cmd
+}
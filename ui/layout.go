@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// cellPadding is the space the table adds around every cell.
+	cellPadding = 2
+	// minTitleWidth is the narrowest the title column gets before less
+	// important columns are hidden to make room.
+	minTitleWidth = 20
+	// minStatusWidth fits "✓ SUCCESS" and "❌ ERROR".
+	minStatusWidth = 10
+	// minTableHeight is the header plus a few rows.
+	minTableHeight = 5
+)
+
+// itemColumns fits the items table into width. Status and Created keep
+// their configured widths and Title gets the rest, up to its configured
+// width. When that leaves too little for titles, Created is hidden and then
+// Status narrowed. A width of 0 means the terminal size isn't known yet.
+func itemColumns(width int, pref columnWidths) []table.Column {
+	title, status, created := pref.Title, pref.Status, pref.Created
+	if width > 0 {
+		fit := func() int {
+			used := status + cellPadding
+			if created > 0 {
+				used += created + cellPadding
+			}
+			return width - used - cellPadding
+		}
+		if fit() < minTitleWidth {
+			created = 0
+		}
+		if fit() < minTitleWidth {
+			status = min(status, minStatusWidth)
+		}
+		title = max(min(title, fit()), 1)
+	}
+	return []table.Column{
+		{Title: "Title", Width: title},
+		{Title: "Status", Width: status},
+		{Title: "Created", Width: created},
+	}
+}
+
+// tableHeight is the height for a table of rows rows, header included, when
+// chrome lines of the view are taken by other content.
+func (m Model) tableHeight(rows, chrome int) int {
+	height := rows + 2
+	if m.Height > 0 {
+		height = min(height, max(m.Height-chrome, minTableHeight))
+	}
+	return height
+}
+
+// newTable returns an empty table in the TUI's style.
+func newTable(highlight bool) table.Model {
+	t := table.New(table.WithFocused(true))
+
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(AccentColor).
+		BorderBottom(true).
+		Bold(true)
+	if highlight {
+		s.Selected = s.Selected.
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(AccentColor).
+			Bold(false)
+	}
+	t.SetStyles(s)
+	return t
+}
+
+// truncate shortens s to fit width cells, ending it with an ellipsis.
+func (m Model) truncate(s string) string {
+	if m.Width <= 0 {
+		return s
+	}
+	return ansi.Truncate(s, m.Width, "…")
+}
+
+// layout resizes everything that depends on the terminal size.
+func (m *Model) layout() {
+	if m.Width > 0 {
+		m.ApiKeyInput.Width = max(min(50, m.Width-4), 10)
+		m.UrlInput.Width = max(min(80, m.Width-4), 10)
+		m.SearchInput.Width = max(min(40, m.Width-4), 10)
+		m.PodcastSearch.Width = max(min(40, m.Width-4), 10)
+		m.DeleteInput.Width = max(min(40, m.Width-4), 10)
+		for i := range m.PodcastForm {
+			m.PodcastForm[i].Width = max(min(60, m.Width-formLabelWidth-2), 10)
+		}
+		for i := range m.ItemForm {
+			m.ItemForm[i].Width = max(min(60, m.Width-formLabelWidth-2), 10)
+		}
+		m.ProgressBar.Width = max(min(40, m.Width-2), 10)
+	}
+	if len(m.Podcasts) > 0 {
+		m.buildPodcastTable()
+	}
+	if len(m.Items) > 0 {
+		m.buildItemsTable()
+	}
+}
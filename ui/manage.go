@@ -0,0 +1,485 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lsherman98/yt-rss-cli/api"
+)
+
+type ItemsChangedMsg struct {
+	// Done is the change in the past tense, e.g. "Deleted".
+	Done    string
+	Deleted bool
+	// PodcastID is the podcast of the items; the message is ignored once
+	// another one is selected.
+	PodcastID string
+	Changed   []api.Item
+	Failed    int
+	Err       error
+}
+
+// DeleteItems deletes items one after another.
+func DeleteItems(ctx context.Context, svc api.Service, podcastID string, items []api.Item) tea.Cmd {
+	return func() tea.Msg {
+		msg := changeItems(items, func(item api.Item) error {
+			return svc.DeleteItem(ctx, podcastID, item.ID)
+		})
+		msg.Done = "Deleted"
+		msg.Deleted = true
+		msg.PodcastID = podcastID
+		return msg
+	}
+}
+
+// HideItems leaves items out of the feed, or puts them back when hidden is
+// false.
+func HideItems(ctx context.Context, svc api.Service, podcastID string, items []api.Item, hidden bool) tea.Cmd {
+	return func() tea.Msg {
+		msg := changeItems(items, func(item api.Item) error {
+			_, err := svc.UpdateItem(ctx, api.UpdateItemRequestBody{PodcastID: podcastID, ItemID: item.ID, Hidden: &hidden})
+			return err
+		})
+		msg.Done = "Hid"
+		msg.PodcastID = podcastID
+		if !hidden {
+			msg.Done = "Unhid"
+		}
+		return msg
+	}
+}
+
+// UpdateItem saves the fields of an item that were changed.
+func UpdateItem(ctx context.Context, svc api.Service, body api.UpdateItemRequestBody) tea.Cmd {
+	return func() tea.Msg {
+		item, err := svc.UpdateItem(ctx, body)
+		if err != nil {
+			return ItemsChangedMsg{Done: "Saved", PodcastID: body.PodcastID, Failed: 1, Err: err}
+		}
+		return ItemsChangedMsg{Done: "Saved", PodcastID: body.PodcastID, Changed: []api.Item{item}}
+	}
+}
+
+// changeItems applies change to each item in turn. Like AddURLs it stops
+// early when the request is cancelled, and then reports the cancellation
+// as Err even if an earlier item failed for another reason.
+func changeItems(items []api.Item, change func(api.Item) error) ItemsChangedMsg {
+	var msg ItemsChangedMsg
+	for i, item := range items {
+		err := change(item)
+		if err == nil {
+			msg.Changed = append(msg.Changed, item)
+			continue
+		}
+		msg.Failed++
+		if msg.Err == nil || isCanceled(err) {
+			msg.Err = err
+		}
+		if isCanceled(err) {
+			msg.Failed += len(items) - i - 1
+			break
+		}
+	}
+	return msg
+}
+
+// itemsChanged reloads the items after a change and reports it.
+func (m *Model) itemsChanged(msg ItemsChangedMsg) tea.Cmd {
+	if msg.Deleted && m.history != nil {
+		for _, item := range msg.Changed {
+			// Forgetting the video only stops it being reported as a
+			// duplicate when it is added again.
+			_ = m.history.Forget(msg.PodcastID, item.ID)
+		}
+	}
+	if isCanceled(msg.Err) || m.SelectedPodcast == nil || m.SelectedPodcast.ID != msg.PodcastID {
+		// The view was left. Items deleted before that are still
+		// forgotten above, but nothing is shown.
+		return nil
+	}
+
+	m.Message = ""
+	if len(msg.Changed) == 0 {
+		// The edit form stays open so the values can be fixed.
+		if m.State == ViewConfirmDeleteItems {
+			m.State = ViewItemsTable
+		}
+		m.showAPIError(msg.Err)
+		return nil
+	}
+
+	m.itemSelected = nil
+	m.State = ViewItemsTable
+	m.Error = ""
+	if len(msg.Changed) == 1 {
+		m.Message = msg.Done + " " + itemTitle(msg.Changed[0])
+	} else {
+		m.Message = fmt.Sprintf("%s %d episodes", msg.Done, len(msg.Changed))
+	}
+	if msg.Failed > 0 {
+		m.showAPIError(msg.Err)
+		m.Error = fmt.Sprintf("%d of %d episode(s) failed: %s", msg.Failed, msg.Failed+len(msg.Changed), m.Error)
+	}
+	return LoadItems(m.requestCtx, m.service, m.SelectedPodcast.ID)
+}
+
+// noItemIDError is shown when an action needs an item the server listed
+// without an ID, which the update and delete calls can't address.
+const noItemIDError = "This episode has no ID, so it can't be changed from here."
+
+// toggleSelected selects the item under the cursor, or unselects it, and
+// moves to the next one. Items without an ID can't be selected, so the IDs
+// in itemSelected are unique per row.
+func (m *Model) toggleSelected() {
+	item, ok := m.selectedItem()
+	if !ok {
+		return
+	}
+	if item.ID == "" {
+		m.Error = noItemIDError
+		return
+	}
+	if m.itemSelected == nil {
+		m.itemSelected = make(map[string]bool)
+	}
+	if m.itemSelected[item.ID] {
+		delete(m.itemSelected, item.ID)
+	} else {
+		m.itemSelected[item.ID] = true
+	}
+	m.ItemsTable.MoveDown(1)
+}
+
+// selectAllShown selects every item in the table unless they all are
+// already selected, in which case they are unselected. Items without an ID
+// are skipped.
+func (m *Model) selectAllShown() {
+	all := true
+	skipped := 0
+	for _, item := range m.itemRows {
+		if item.ID == "" {
+			skipped++
+			continue
+		}
+		all = all && m.itemSelected[item.ID]
+	}
+	if skipped == len(m.itemRows) {
+		all = false
+	}
+	if m.itemSelected == nil {
+		m.itemSelected = make(map[string]bool)
+	}
+	for _, item := range m.itemRows {
+		switch {
+		case item.ID == "":
+		case all:
+			delete(m.itemSelected, item.ID)
+		default:
+			m.itemSelected[item.ID] = true
+		}
+	}
+	if skipped > 0 && !all {
+		m.Error = fmt.Sprintf("%d episode(s) without an ID can't be selected.", skipped)
+	}
+}
+
+// pruneSelection drops selected items that no longer exist.
+func (m *Model) pruneSelection() {
+	for id := range m.itemSelected {
+		found := false
+		for _, item := range m.Items {
+			found = found || item.ID == id
+		}
+		if !found {
+			delete(m.itemSelected, id)
+		}
+	}
+}
+
+// targetItems are the items an action applies to: the selected ones, or
+// else the one under the cursor. An item under the cursor without an ID is
+// reported instead.
+func (m *Model) targetItems() []api.Item {
+	if len(m.itemSelected) == 0 {
+		item, ok := m.selectedItem()
+		if !ok {
+			return nil
+		}
+		if item.ID == "" {
+			m.Error = noItemIDError
+			return nil
+		}
+		return []api.Item{item}
+	}
+	var items []api.Item
+	for _, item := range m.Items {
+		if m.itemSelected[item.ID] {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// updateManageItems handles the keys of the items table that select, edit,
+// hide and delete items. It reports whether the key was one of them.
+func (m *Model) updateManageItems(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case " ":
+		m.toggleSelected()
+	case "*":
+		m.selectAllShown()
+	case "e":
+		item, ok := m.selectedItem()
+		if !ok {
+			return nil, true
+		}
+		if item.ID == "" {
+			m.Error = noItemIDError
+			return nil, true
+		}
+		m.openEditItem(item)
+		return nil, true
+	case "d":
+		if items := m.targetItems(); len(items) > 0 {
+			m.confirmItems = items
+			m.Error = ""
+			m.Message = ""
+			m.State = ViewConfirmDeleteItems
+		}
+		return nil, true
+	case "h":
+		items := m.targetItems()
+		if len(items) == 0 {
+			return nil, true
+		}
+		// Hide the items unless they all are hidden already.
+		hidden := false
+		for _, item := range items {
+			hidden = hidden || !item.Hidden
+		}
+		m.Error = ""
+		m.Message = "Updating..."
+		return HideItems(m.requestCtx, m.service, m.SelectedPodcast.ID, items, hidden), true
+	case "esc":
+		if len(m.itemSelected) == 0 {
+			return nil, false
+		}
+		m.itemSelected = nil
+	default:
+		return nil, false
+	}
+	m.buildItemsTable()
+	return nil, true
+}
+
+// The fields of the episode form, in order.
+const (
+	itemFieldTitle = iota
+	itemFieldDescription
+	itemFieldPublished
+)
+
+var itemFieldLabels = []string{
+	itemFieldTitle:       "Title",
+	itemFieldDescription: "Description",
+	itemFieldPublished:   "Published",
+}
+
+// publishedLayout is how publish dates are shown in the episode form.
+const publishedLayout = "2006-01-02 15:04"
+
+func newItemForm() []textinput.Model {
+	inputs := make([]textinput.Model, len(itemFieldLabels))
+	for i := range inputs {
+		inputs[i] = textinput.New()
+		inputs[i].Prompt = ""
+		inputs[i].Width = 60
+	}
+	inputs[itemFieldTitle].CharLimit = 200
+	inputs[itemFieldDescription].CharLimit = 4000
+	inputs[itemFieldDescription].Placeholder = "Shown in podcast apps"
+	inputs[itemFieldPublished].CharLimit = 30
+	return inputs
+}
+
+func itemValues(item api.Item) []string {
+	published := ""
+	if item.Published != "" {
+		published = item.PublishedAt().Local().Format(publishedLayout)
+	}
+	return []string{
+		itemFieldTitle:       item.Title,
+		itemFieldDescription: item.Description,
+		itemFieldPublished:   published,
+	}
+}
+
+func (m *Model) openEditItem(item api.Item) {
+	m.editItem = item
+	for i, value := range itemValues(item) {
+		m.ItemForm[i].SetValue(value)
+		m.ItemForm[i].CursorEnd()
+	}
+	m.ItemForm[itemFieldPublished].Placeholder = "YYYY-MM-DD HH:MM"
+	if t := item.CreatedAt(); !t.IsZero() {
+		m.ItemForm[itemFieldPublished].Placeholder = t.Local().Format(publishedLayout)
+	}
+	m.formFocus = focusInput(m.ItemForm, itemFieldTitle)
+	m.Error = ""
+	m.Message = ""
+	m.State = ViewEditItem
+}
+
+func (m *Model) backToItems() {
+	for i := range m.ItemForm {
+		m.ItemForm[i].Blur()
+	}
+	m.Error = ""
+	m.Message = ""
+	m.State = ViewItemsTable
+}
+
+// saveItem sends the fields of the episode form that were changed.
+func (m *Model) saveItem() tea.Cmd {
+	values := make([]string, len(m.ItemForm))
+	for i, input := range m.ItemForm {
+		values[i] = strings.TrimSpace(input.Value())
+	}
+	if values[itemFieldTitle] == "" {
+		m.formFocus = focusInput(m.ItemForm, itemFieldTitle)
+		m.Error = "The title can't be empty"
+		return nil
+	}
+
+	old := itemValues(m.editItem)
+	changed := func(field int) *string {
+		if values[field] == old[field] {
+			return nil
+		}
+		return &values[field]
+	}
+	body := api.UpdateItemRequestBody{
+		PodcastID:   m.SelectedPodcast.ID,
+		ItemID:      m.editItem.ID,
+		Title:       changed(itemFieldTitle),
+		Description: changed(itemFieldDescription),
+		Published:   changed(itemFieldPublished),
+	}
+	if body.Published != nil && *body.Published != "" {
+		t, err := api.ParseDate(*body.Published)
+		if err != nil {
+			m.formFocus = focusInput(m.ItemForm, itemFieldPublished)
+			m.Error = err.Error()
+			return nil
+		}
+		published := t.UTC().Format(time.RFC3339)
+		body.Published = &published
+	}
+	if body.Title == nil && body.Description == nil && body.Published == nil {
+		m.backToItems()
+		return nil
+	}
+
+	m.Error = ""
+	m.Message = "Saving..."
+	return UpdateItem(m.requestCtx, m.service, body)
+}
+
+func (m *Model) updateEditItem(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		m.Polling = false
+		m.cancelRequests()
+		return tea.Quit
+	case "esc":
+		m.backToItems()
+		return nil
+	case "tab", "down":
+		m.formFocus = focusInput(m.ItemForm, m.formFocus+1)
+		return nil
+	case "shift+tab", "up":
+		m.formFocus = focusInput(m.ItemForm, m.formFocus-1)
+		return nil
+	case "enter":
+		return m.saveItem()
+	}
+
+	var cmd tea.Cmd
+	m.ItemForm[m.formFocus], cmd = m.ItemForm[m.formFocus].Update(msg)
+	return cmd
+}
+
+func (m Model) viewEditItem() string {
+	var s strings.Builder
+
+	s.WriteString(TitleStyle.Render(m.truncate("Edit episode: " + itemTitle(m.editItem))))
+	s.WriteString("\n")
+	viewForm(&s, itemFieldLabels, m.ItemForm, m.formFocus)
+	s.WriteString("\n")
+	s.WriteString(MutedStyle.Render(m.truncate("The feed is ordered by publish date; empty uses the date it was added.")))
+	s.WriteString("\n")
+
+	if m.Message != "" {
+		s.WriteString(MutedStyle.Render(m.Message))
+		s.WriteString("\n")
+	}
+	if m.Error != "" {
+		s.WriteString(ErrorStyle.Render("Error: " + m.Error))
+		s.WriteString("\n")
+	}
+	s.WriteString(HelpStyle.Render("Tab/↑/↓: Next field • Enter: Save • Esc: Cancel • Ctrl+c: Quit"))
+	return s.String()
+}
+
+// maxConfirmLines limits the episodes listed by the delete confirmation.
+const maxConfirmLines = 8
+
+func (m *Model) updateConfirmDeleteItems(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		m.Polling = false
+		m.cancelRequests()
+		return tea.Quit
+	case "y", "enter":
+		m.Error = ""
+		m.Message = fmt.Sprintf("Deleting %d episode(s)...", len(m.confirmItems))
+		return DeleteItems(m.requestCtx, m.service, m.SelectedPodcast.ID, m.confirmItems)
+	case "n", "esc":
+		m.backToItems()
+	}
+	return nil
+}
+
+func (m Model) viewConfirmDeleteItems() string {
+	var s strings.Builder
+
+	s.WriteString(TitleStyle.Render(m.truncate(fmt.Sprintf("Delete %d episode(s) from %s?", len(m.confirmItems), m.SelectedPodcast.Title))))
+	s.WriteString("\n")
+	for i, item := range m.confirmItems {
+		if i == maxConfirmLines-1 && len(m.confirmItems) > maxConfirmLines {
+			s.WriteString(MutedStyle.Render(fmt.Sprintf("  … and %d more", len(m.confirmItems)-i)))
+			s.WriteString("\n")
+			break
+		}
+		s.WriteString(m.truncate("  • " + itemTitle(item)))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	s.WriteString(ErrorStyle.Render(m.truncate("They are removed from the feed and their audio is deleted. This can't be undone.")))
+	s.WriteString("\n")
+
+	if m.Message != "" {
+		s.WriteString(MutedStyle.Render(m.Message))
+		s.WriteString("\n")
+	}
+	if m.Error != "" {
+		s.WriteString(ErrorStyle.Render("Error: " + m.Error))
+		s.WriteString("\n")
+	}
+	s.WriteString(HelpStyle.Render("y: Delete • n/Esc: Cancel • Ctrl+c: Quit"))
+	return s.String()
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/history"
+	"github.com/lsherman98/yt-rss-cli/youtube"
+)
+
+// Option configures the model built by InitialModel.
+type Option func(*Model)
+
+// Profiles lets the TUI list and switch between the configured accounts.
+// Switch returns the service to use for the new profile.
+type Profiles interface {
+	Names() []string
+	Active() string
+	Switch(name string) (api.Service, error)
+}
+
+// WithProfiles adds a "Switch Profile" entry to the main menu.
+func WithProfiles(p Profiles) Option {
+	return func(m *Model) {
+		m.profiles = p
+	}
+}
+
+// WithPollInterval sets how often the items table refreshes while episodes
+// are processing.
+func WithPollInterval(d time.Duration) Option {
+	return func(m *Model) {
+		m.pollInterval = d
+	}
+}
+
+// WithColumnWidths sets the widths of the items table columns. The title
+// width is a maximum; the title column shrinks to fit narrower terminals.
+func WithColumnWidths(title, status, created int) Option {
+	return func(m *Model) {
+		m.columnWidths = columnWidths{Title: title, Status: status, Created: created}
+	}
+}
+
+// WithHistory checks new URLs against, and records them in, the local
+// submission history in addition to the podcast's items.
+func WithHistory(store *history.Store) Option {
+	return func(m *Model) {
+		m.history = store
+	}
+}
+
+// Feeds lists the videos of a playlist or channel. *youtube.FeedClient
+// implements it.
+type Feeds interface {
+	Fetch(ctx context.Context, list youtube.List) (*youtube.Feed, error)
+}
+
+// WithFeeds sets where playlist and channel links are expanded from.
+func WithFeeds(f Feeds) Option {
+	return func(m *Model) {
+		m.feeds = f
+	}
+}
+
+// WithRecents pins the recently used podcasts to the top of the podcast
+// picker and records each podcast picked.
+func WithRecents(r Recents) Option {
+	return func(m *Model) {
+		m.recents = r
+	}
+}
+
+// WithClipboard sets how text is copied from the item detail view. By default
+// it goes to the system clipboard, or through the terminal where there is none.
+func WithClipboard(copy func(text string) error) Option {
+	return func(m *Model) {
+		m.copy = copy
+	}
+}
@@ -0,0 +1,279 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/sahilm/fuzzy"
+)
+
+// Recents remembers the podcasts picked most recently, which the podcast
+// picker pins to the top. *history.RecentPodcasts implements it.
+type Recents interface {
+	IDs() []string
+	Use(podcastID string) error
+}
+
+type ItemCountsMsg struct {
+	Counts map[string]int
+}
+
+// countConcurrency limits the requests made by LoadItemCounts.
+const countConcurrency = 4
+
+// LoadItemCounts fetches the number of items in each podcast. Podcasts
+// whose items can't be loaded are left out.
+func LoadItemCounts(ctx context.Context, svc api.Service, podcasts []api.Podcast) tea.Cmd {
+	return func() tea.Msg {
+		counts := make(map[string]int)
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, countConcurrency)
+		for _, p := range podcasts {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				items, err := svc.GetPodcastItems(ctx, p.ID)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				counts[p.ID] = len(items)
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		if ctx.Err() != nil {
+			// The picker was left; don't report every count as missing.
+			return ItemCountsMsg{}
+		}
+		return ItemCountsMsg{Counts: counts}
+	}
+}
+
+// uncountedPodcasts returns the podcasts whose episode count is not known
+// yet, either because the picker wasn't opened before or because loading
+// the count failed.
+func (m Model) uncountedPodcasts() []api.Podcast {
+	var uncounted []api.Podcast
+	for _, p := range m.Podcasts {
+		if _, ok := m.podcastCounts[p.ID]; !ok {
+			uncounted = append(uncounted, p)
+		}
+	}
+	return uncounted
+}
+
+// openPodcastPicker clears the filter of the podcast picker and loads the
+// podcasts into it.
+func (m *Model) openPodcastPicker() tea.Cmd {
+	m.State = ViewSelectPodcast
+	m.Error = ""
+	m.Message = ""
+	m.PodcastSearch.SetValue("")
+	m.PodcastSearch.Focus()
+	m.PodcastTable = newTable(true)
+	return LoadPodcasts(m.resetRequests(), m.service)
+}
+
+// pickerPodcasts returns the podcasts to list. Without a filter the
+// recently used ones come first, most recent at the top; with one, the
+// matches are ordered best first.
+func (m Model) pickerPodcasts() []api.Podcast {
+	query := strings.TrimSpace(m.PodcastSearch.Value())
+	if query != "" {
+		titles := make([]string, len(m.Podcasts))
+		for i, p := range m.Podcasts {
+			titles[i] = p.Title
+		}
+		var matched []api.Podcast
+		for _, match := range fuzzy.Find(query, titles) {
+			matched = append(matched, m.Podcasts[match.Index])
+		}
+		return matched
+	}
+
+	var pinned, rest []api.Podcast
+	for _, id := range m.recentIDs() {
+		if i := slices.IndexFunc(m.Podcasts, func(p api.Podcast) bool { return p.ID == id }); i >= 0 {
+			pinned = append(pinned, m.Podcasts[i])
+		}
+	}
+	for _, p := range m.Podcasts {
+		if !slices.ContainsFunc(pinned, func(q api.Podcast) bool { return q.ID == p.ID }) {
+			rest = append(rest, p)
+		}
+	}
+	return append(pinned, rest...)
+}
+
+func (m Model) recentIDs() []string {
+	if m.recents == nil {
+		return nil
+	}
+	return m.recents.IDs()
+}
+
+// podcastsChrome is the number of lines the podcast picker needs besides
+// the table: the title, the filter, an error or message, the position and
+// the two lines of help.
+const podcastsChrome = 10
+
+// countWidth fits the episode count column.
+const countWidth = 8
+
+func (m *Model) buildPodcastTable() {
+	m.podcastRows = m.pickerPodcasts()
+	recent := m.recentIDs()
+
+	width := 60
+	if m.Width > 0 {
+		width = max(min(width, m.Width-countWidth-2*cellPadding), 1)
+	}
+
+	rows := []table.Row{}
+	for _, p := range m.podcastRows {
+		title := p.Title
+		if slices.Contains(recent, p.ID) {
+			title = "★ " + title
+		}
+		count := "…"
+		if n, ok := m.podcastCounts[p.ID]; ok {
+			count = fmt.Sprint(n)
+		} else if m.podcastCounts != nil && !m.countsLoading {
+			count = "-"
+		}
+		rows = append(rows, table.Row{title, count})
+	}
+
+	t := &m.PodcastTable
+	t.SetColumns([]table.Column{
+		{Title: "Title", Width: width},
+		{Title: "Episodes", Width: countWidth},
+	})
+	t.SetRows(rows)
+	t.SetHeight(m.tableHeight(len(rows), podcastsChrome))
+	t.SetCursor(max(min(t.Cursor(), len(rows)-1), 0))
+}
+
+// updatePodcastPicker handles keys in the podcast picker. Typed text
+// filters the list, so only ctrl+c quits and the podcast actions are on
+// ctrl keys too.
+func (m *Model) updatePodcastPicker(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		m.cancelRequests()
+		return tea.Quit
+	case "ctrl+n":
+		m.openPodcastForm(nil)
+		return nil
+	case "ctrl+e":
+		if podcast, ok := m.pickedPodcast(); ok {
+			m.openPodcastForm(&podcast)
+		}
+		return nil
+	case "ctrl+d":
+		if podcast, ok := m.pickedPodcast(); ok {
+			m.openDeletePodcast(podcast)
+		}
+		return nil
+	case "esc":
+		if m.PodcastSearch.Value() != "" {
+			m.PodcastSearch.SetValue("")
+			m.buildPodcastTable()
+			return nil
+		}
+		m.resetRequests()
+		m.PodcastSearch.Blur()
+		m.State = ViewMainMenu
+		return nil
+	case "enter":
+		podcast, ok := m.pickedPodcast()
+		if !ok {
+			return nil
+		}
+		if m.recents != nil {
+			// Remembering the choice is a convenience; failing to save it
+			// shouldn't stop the user from adding a URL.
+			_ = m.recents.Use(podcast.ID)
+		}
+		m.SelectedPodcast = &podcast
+		m.Items = nil
+		m.ItemsTable = newTable(false)
+		m.itemFilter = itemFilter{sortBy: m.itemFilter.sortBy, reverse: m.itemFilter.reverse}
+		m.PodcastSearch.Blur()
+		m.State = ViewEnterURL
+		m.UrlInput.Focus()
+		m.UrlInput.SetValue("")
+		return nil
+	}
+
+	var cmd tea.Cmd
+	if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace || msg.Type == tea.KeyBackspace {
+		before := m.PodcastSearch.Value()
+		m.PodcastSearch, cmd = m.PodcastSearch.Update(msg)
+		if m.PodcastSearch.Value() != before {
+			m.PodcastTable.SetCursor(0)
+		}
+	} else {
+		m.PodcastTable, cmd = m.PodcastTable.Update(msg)
+	}
+	m.buildPodcastTable()
+	return cmd
+}
+
+// pickedPodcast returns the podcast under the cursor.
+func (m Model) pickedPodcast() (api.Podcast, bool) {
+	i := m.PodcastTable.Cursor()
+	if i < 0 || i >= len(m.podcastRows) {
+		return api.Podcast{}, false
+	}
+	return m.podcastRows[i], true
+}
+
+func (m Model) viewPodcastPicker() string {
+	var s strings.Builder
+
+	s.WriteString(TitleStyle.Render("Select a Podcast"))
+	s.WriteString("\n")
+	s.WriteString(m.PodcastSearch.View())
+	s.WriteString("\n")
+	switch {
+	case len(m.Podcasts) == 0:
+		s.WriteString("No podcasts found. Ctrl+n creates one.\n")
+	case len(m.podcastRows) == 0:
+		s.WriteString(MutedStyle.Render("No podcasts match. Esc clears the filter."))
+		s.WriteString("\n")
+	default:
+		s.WriteString(m.PodcastTable.View())
+		s.WriteString("\n")
+		if len(m.podcastRows) > m.PodcastTable.Height() {
+			s.WriteString(MutedStyle.Render(fmt.Sprintf("%d of %d • PgUp/PgDn: Page", m.PodcastTable.Cursor()+1, len(m.podcastRows))))
+			s.WriteString("\n")
+		}
+	}
+	if m.Message != "" {
+		s.WriteString(SuccessStyle.Render(m.Message))
+		s.WriteString("\n")
+	}
+	if m.Error != "" {
+		s.WriteString(ErrorStyle.Render("Error: " + m.Error))
+		s.WriteString("\n")
+	}
+	help := "Type to filter • ↑/↓: Move • Enter: Select • Esc: Back • Ctrl+c: Quit"
+	if len(m.recentIDs()) > 0 {
+		help += " • ★ Recent"
+	}
+	s.WriteString(HelpStyle.Render(help))
+	s.WriteString("\n")
+	s.WriteString(MutedStyle.Render("Ctrl+n: New podcast • Ctrl+e: Edit • Ctrl+d: Delete"))
+	return s.String()
+}
@@ -0,0 +1,347 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lsherman98/yt-rss-cli/api"
+)
+
+type PodcastSavedMsg struct {
+	Podcast api.Podcast
+	Created bool
+	Err     error
+}
+
+type PodcastDeletedMsg struct {
+	Podcast api.Podcast
+	Err     error
+}
+
+// CreatePodcast creates a podcast from the form's values.
+func CreatePodcast(ctx context.Context, svc api.Service, body api.CreatePodcastRequestBody) tea.Cmd {
+	return func() tea.Msg {
+		podcast, err := svc.CreatePodcast(ctx, body)
+		return PodcastSavedMsg{Podcast: podcast, Created: true, Err: err}
+	}
+}
+
+// UpdatePodcast saves the fields of a podcast that were changed.
+func UpdatePodcast(ctx context.Context, svc api.Service, body api.UpdatePodcastRequestBody) tea.Cmd {
+	return func() tea.Msg {
+		podcast, err := svc.UpdatePodcast(ctx, body)
+		return PodcastSavedMsg{Podcast: podcast, Err: err}
+	}
+}
+
+// DeletePodcast deletes a podcast and its episodes.
+func DeletePodcast(ctx context.Context, svc api.Service, podcast api.Podcast) tea.Cmd {
+	return func() tea.Msg {
+		return PodcastDeletedMsg{Podcast: podcast, Err: svc.DeletePodcast(ctx, podcast.ID)}
+	}
+}
+
+// The fields of the podcast form, in order.
+const (
+	fieldTitle = iota
+	fieldDescription
+	fieldAuthor
+	fieldArtwork
+	fieldCategory
+)
+
+var podcastFields = []struct {
+	label       string
+	placeholder string
+	limit       int
+}{
+	fieldTitle:       {"Title", "Required", 200},
+	fieldDescription: {"Description", "Shown in podcast apps", 4000},
+	fieldAuthor:      {"Author", "Shown in podcast apps", 200},
+	fieldArtwork:     {"Artwork", "https://example.com/cover.jpg", 500},
+	fieldCategory:    {"Category", "e.g. Technology", 100},
+}
+
+// formLabelWidth fits the longest field label and a space.
+const formLabelWidth = 13
+
+func newPodcastForm() []textinput.Model {
+	inputs := make([]textinput.Model, len(podcastFields))
+	for i, f := range podcastFields {
+		inputs[i] = textinput.New()
+		inputs[i].Prompt = ""
+		inputs[i].Placeholder = f.placeholder
+		inputs[i].CharLimit = f.limit
+		inputs[i].Width = 60
+	}
+	return inputs
+}
+
+func podcastValues(p api.Podcast) []string {
+	return []string{
+		fieldTitle:       p.Title,
+		fieldDescription: p.Description,
+		fieldAuthor:      p.Author,
+		fieldArtwork:     p.Artwork,
+		fieldCategory:    p.Category,
+	}
+}
+
+// openPodcastForm shows the form for a new podcast, or for editing podcast
+// when it isn't nil.
+func (m *Model) openPodcastForm(podcast *api.Podcast) {
+	m.editing = podcast
+	values := make([]string, len(podcastFields))
+	if podcast != nil {
+		values = podcastValues(*podcast)
+	}
+	for i := range m.PodcastForm {
+		m.PodcastForm[i].SetValue(values[i])
+		m.PodcastForm[i].CursorEnd()
+	}
+	m.formFocus = focusInput(m.PodcastForm, fieldTitle)
+	m.PodcastSearch.Blur()
+	m.Error = ""
+	m.Message = ""
+	m.State = ViewPodcastForm
+}
+
+// focusInput moves the focus of a form to input i, wrapping around at
+// either end, and returns the focused index.
+func focusInput(inputs []textinput.Model, i int) int {
+	n := len(inputs)
+	i = (i%n + n) % n
+	for j := range inputs {
+		if j == i {
+			inputs[j].Focus()
+		} else {
+			inputs[j].Blur()
+		}
+	}
+	return i
+}
+
+// viewForm renders labelled inputs, highlighting the label of the focused
+// one.
+func viewForm(s *strings.Builder, labels []string, inputs []textinput.Model, focus int) {
+	for i, input := range inputs {
+		label := lipgloss.NewStyle().Width(formLabelWidth)
+		if i == focus {
+			label = label.Foreground(AccentColor)
+		}
+		s.WriteString(label.Render(labels[i]))
+		s.WriteString(input.View())
+		s.WriteString("\n")
+	}
+}
+
+// backToPicker leaves the form or the delete confirmation without
+// reloading the podcasts.
+func (m *Model) backToPicker() {
+	m.resetRequests()
+	for i := range m.PodcastForm {
+		m.PodcastForm[i].Blur()
+	}
+	m.DeleteInput.Blur()
+	m.Error = ""
+	m.Message = ""
+	m.PodcastSearch.Focus()
+	m.State = ViewSelectPodcast
+}
+
+// savePodcast creates the podcast, or sends the fields that were changed
+// when editing one.
+func (m *Model) savePodcast() tea.Cmd {
+	values := make([]string, len(m.PodcastForm))
+	for i, input := range m.PodcastForm {
+		values[i] = strings.TrimSpace(input.Value())
+	}
+	if values[fieldTitle] == "" {
+		m.formFocus = focusInput(m.PodcastForm, fieldTitle)
+		m.Error = "The title can't be empty"
+		return nil
+	}
+	m.Error = ""
+
+	if m.editing == nil {
+		m.Message = "Creating " + values[fieldTitle] + "..."
+		return CreatePodcast(m.requestCtx, m.service, api.CreatePodcastRequestBody{
+			Title:       values[fieldTitle],
+			Description: values[fieldDescription],
+			Author:      values[fieldAuthor],
+			Artwork:     values[fieldArtwork],
+			Category:    values[fieldCategory],
+		})
+	}
+
+	old := podcastValues(*m.editing)
+	changed := func(field int) *string {
+		if values[field] == old[field] {
+			return nil
+		}
+		return &values[field]
+	}
+	body := api.UpdatePodcastRequestBody{
+		PodcastID:   m.editing.ID,
+		Title:       changed(fieldTitle),
+		Description: changed(fieldDescription),
+		Author:      changed(fieldAuthor),
+		Artwork:     changed(fieldArtwork),
+		Category:    changed(fieldCategory),
+	}
+	if body == (api.UpdatePodcastRequestBody{PodcastID: m.editing.ID}) {
+		m.backToPicker()
+		return nil
+	}
+	m.Message = "Saving " + values[fieldTitle] + "..."
+	return UpdatePodcast(m.requestCtx, m.service, body)
+}
+
+func (m *Model) updatePodcastForm(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		m.cancelRequests()
+		return tea.Quit
+	case "esc":
+		m.backToPicker()
+		return nil
+	case "tab", "down":
+		m.formFocus = focusInput(m.PodcastForm, m.formFocus+1)
+		return nil
+	case "shift+tab", "up":
+		m.formFocus = focusInput(m.PodcastForm, m.formFocus-1)
+		return nil
+	case "enter":
+		return m.savePodcast()
+	}
+
+	var cmd tea.Cmd
+	m.PodcastForm[m.formFocus], cmd = m.PodcastForm[m.formFocus].Update(msg)
+	return cmd
+}
+
+func (m Model) viewPodcastForm() string {
+	var s strings.Builder
+
+	title := "New Podcast"
+	if m.editing != nil {
+		title = "Edit: " + m.editing.Title
+	}
+	s.WriteString(TitleStyle.Render(m.truncate(title)))
+	s.WriteString("\n")
+
+	labels := make([]string, len(podcastFields))
+	for i, f := range podcastFields {
+		labels[i] = f.label
+	}
+	viewForm(&s, labels, m.PodcastForm, m.formFocus)
+
+	if m.Message != "" {
+		s.WriteString("\n")
+		s.WriteString(MutedStyle.Render(m.Message))
+		s.WriteString("\n")
+	}
+	if m.Error != "" {
+		s.WriteString(ErrorStyle.Render("Error: " + m.Error))
+		s.WriteString("\n")
+	}
+	s.WriteString(HelpStyle.Render("Tab/↑/↓: Next field • Enter: Save • Esc: Cancel • Ctrl+c: Quit"))
+	return s.String()
+}
+
+// openDeletePodcast asks for the title of podcast before deleting it.
+func (m *Model) openDeletePodcast(podcast api.Podcast) {
+	m.deleting = podcast
+	m.DeleteInput.SetValue("")
+	m.DeleteInput.Placeholder = podcast.Title
+	m.DeleteInput.Focus()
+	m.PodcastSearch.Blur()
+	m.Error = ""
+	m.Message = ""
+	m.State = ViewConfirmDeletePodcast
+}
+
+func (m *Model) updateDeletePodcast(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		m.cancelRequests()
+		return tea.Quit
+	case "esc":
+		m.backToPicker()
+		return nil
+	case "enter":
+		if m.DeleteInput.Value() != m.deleting.Title {
+			m.Error = "The title doesn't match"
+			return nil
+		}
+		m.Error = ""
+		m.Message = "Deleting " + m.deleting.Title + "..."
+		return DeletePodcast(m.requestCtx, m.service, m.deleting)
+	}
+
+	var cmd tea.Cmd
+	m.DeleteInput, cmd = m.DeleteInput.Update(msg)
+	return cmd
+}
+
+func (m Model) viewDeletePodcast() string {
+	var s strings.Builder
+
+	s.WriteString(TitleStyle.Render(m.truncate("Delete " + m.deleting.Title)))
+	s.WriteString("\n")
+
+	episodes := "all of its episodes"
+	if n, ok := m.podcastCounts[m.deleting.ID]; ok {
+		episodes = fmt.Sprintf("its %d episode(s)", n)
+	}
+	warning := fmt.Sprintf("This deletes the podcast, %s and its public feed. It can't be undone.", episodes)
+	width := m.Width
+	if width <= 0 {
+		width = 80
+	}
+	s.WriteString(ErrorStyle.Width(width).Render(warning))
+	s.WriteString("\n\n")
+	s.WriteString("Type the podcast's title to confirm:\n")
+	s.WriteString(m.DeleteInput.View())
+	s.WriteString("\n")
+
+	if m.Message != "" {
+		s.WriteString(MutedStyle.Render(m.Message))
+		s.WriteString("\n")
+	}
+	if m.Error != "" {
+		s.WriteString(ErrorStyle.Render("Error: " + m.Error))
+		s.WriteString("\n")
+	}
+	s.WriteString(HelpStyle.Render("Enter: Delete • Esc: Cancel • Ctrl+c: Quit"))
+	return s.String()
+}
+
+// podcastSaved returns to the picker with the podcasts reloaded.
+func (m *Model) podcastSaved(msg PodcastSavedMsg) tea.Cmd {
+	if m.SelectedPodcast != nil && m.SelectedPodcast.ID == msg.Podcast.ID {
+		m.SelectedPodcast = &msg.Podcast
+	}
+	cmd := m.openPodcastPicker()
+	if msg.Created {
+		m.Message = "Created " + msg.Podcast.Title
+	} else {
+		m.Message = "Saved " + msg.Podcast.Title
+	}
+	return cmd
+}
+
+func (m *Model) podcastDeleted(msg PodcastDeletedMsg) tea.Cmd {
+	if m.SelectedPodcast != nil && m.SelectedPodcast.ID == msg.Podcast.ID {
+		m.SelectedPodcast = nil
+		m.Items = nil
+	}
+	delete(m.podcastCounts, msg.Podcast.ID)
+	cmd := m.openPodcastPicker()
+	m.Message = "Deleted " + msg.Podcast.Title
+	return cmd
+}
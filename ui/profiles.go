@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m *Model) buildProfileList() {
+	names := m.profiles.Names()
+	items := make([]list.Item, len(names))
+	selected := 0
+	for i, name := range names {
+		if name == m.profiles.Active() {
+			items[i] = menuItem(name + " (active)")
+			selected = i
+		} else {
+			items[i] = menuItem(name)
+		}
+	}
+
+	l := list.New(items, itemDelegate{}, 40, min(len(items), 10)+4)
+	l.Title = "Switch Profile"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(false)
+	l.Styles.Title = TitleStyle
+	l.Select(selected)
+
+	m.ProfileList = l
+	m.profileNames = names
+}
+
+// switchProfile activates the profile under the cursor and re-checks the API
+// key, since each profile has its own.
+func (m *Model) switchProfile() tea.Cmd {
+	index := m.ProfileList.Index()
+	if index < 0 || index >= len(m.profileNames) {
+		return nil
+	}
+	name := m.profileNames[index]
+
+	// Cancel the old profile's requests before its client is replaced, so
+	// no late response is shown under the new profile.
+	m.Polling = false
+	m.resetRequests()
+	service, err := m.profiles.Switch(name)
+	if err != nil {
+		m.Error = err.Error()
+		return nil
+	}
+	m.service = service
+
+	m.Usage = nil
+	m.Podcasts = nil
+	m.podcastCounts = nil
+	m.SelectedPodcast = nil
+	m.Items = nil
+	m.Error = ""
+	m.Message = "Switched to profile " + name
+	m.State = ViewMainMenu
+	return CheckAPIKey(m.service)
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/history"
+)
+
+type ItemRetriedMsg struct {
+	URL  string
+	Item api.Item
+	Err  error
+}
+
+// RetryItem resubmits the video a failed item was created from.
+func RetryItem(ctx context.Context, svc api.Service, podcastID, url string) tea.Cmd {
+	return func() tea.Msg {
+		item, err := svc.AddUrlToPodcast(ctx, podcastID, url)
+		return ItemRetriedMsg{URL: url, Item: item, Err: err}
+	}
+}
+
+// retryItem resubmits item if it failed and its video is known.
+func (m *Model) retryItem(item api.Item) tea.Cmd {
+	m.Message = ""
+	if item.Status != api.StatusError {
+		m.Error = "Only failed items can be retried"
+		return nil
+	}
+	url := history.SourceURL(m.history, m.SelectedPodcast.ID, item)
+	if url == "" {
+		m.Error = "The video this item was created from is unknown, add it again from the main menu"
+		return nil
+	}
+	m.Error = ""
+	m.Message = "Retrying " + url + "..."
+	return RetryItem(m.requestCtx, m.service, m.SelectedPodcast.ID, url)
+}
+
+// retried records a successful retry and resumes polling for the new item.
+func (m *Model) retried(msg ItemRetriedMsg) tea.Cmd {
+	m.Message = "Resubmitted " + msg.URL
+	if m.history != nil {
+		if n, err := m.history.RecordRetry(m.SelectedPodcast.ID, msg.URL, msg.Item.ID, time.Now()); err == nil && n > 0 {
+			m.Message += fmt.Sprintf(" (retry %d)", n)
+		}
+	}
+	if m.Polling {
+		// The pending tick picks up the new item.
+		return nil
+	}
+	m.Polling = true
+	return LoadItems(m.requestCtx, m.service, m.SelectedPodcast.ID)
+}
+
+// retries returns how often the item's video was retried, if known.
+func (m Model) retries(item api.Item) int {
+	if m.history == nil {
+		return 0
+	}
+	return m.history.Retries(m.SelectedPodcast.ID, history.SourceURL(m.history, m.SelectedPodcast.ID, item))
+}
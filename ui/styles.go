@@ -2,21 +2,59 @@ package ui
 
 import "github.com/charmbracelet/lipgloss"
 
+// Theme holds the colors used throughout the TUI, as hex strings or ANSI
+// color numbers.
+type Theme struct {
+	Accent  string
+	Muted   string
+	Error   string
+	Success string
+}
+
+var DefaultTheme = Theme{
+	Accent:  "#7D56F4",
+	Muted:   "#626262",
+	Error:   "#FF0000",
+	Success: "#04B575",
+}
+
 var (
+	AccentColor lipgloss.Color
+	MutedColor  lipgloss.Color
+
+	TitleStyle   lipgloss.Style
+	HelpStyle    lipgloss.Style
+	MutedStyle   lipgloss.Style
+	ErrorStyle   lipgloss.Style
+	SuccessStyle lipgloss.Style
+)
+
+func init() {
+	SetTheme(DefaultTheme)
+}
+
+// SetTheme rebuilds the shared styles from t. Call it before InitialModel.
+func SetTheme(t Theme) {
+	AccentColor = lipgloss.Color(t.Accent)
+	MutedColor = lipgloss.Color(t.Muted)
+
 	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#7D56F4")).
-			MarginBottom(1)
+		Bold(true).
+		Foreground(AccentColor).
+		MarginBottom(1)
 
 	HelpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#626262")).
-			MarginTop(1)
+		Foreground(MutedColor).
+		MarginTop(1)
+
+	MutedStyle = lipgloss.NewStyle().
+		Foreground(MutedColor)
 
 	ErrorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF0000")).
-			Bold(true)
+		Foreground(lipgloss.Color(t.Error)).
+		Bold(true)
 
 	SuccessStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#04B575")).
-			Bold(true)
-)
+		Foreground(lipgloss.Color(t.Success)).
+		Bold(true)
+}
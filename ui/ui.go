@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strings"
@@ -14,6 +15,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/history"
+	"github.com/lsherman98/yt-rss-cli/output"
+	"github.com/lsherman98/yt-rss-cli/youtube"
 )
 
 type ViewState int
@@ -25,6 +29,14 @@ const (
 	ViewEnterURL
 	ViewItemsTable
 	ViewFatalError
+	ViewSelectProfile
+	ViewConfirmDuplicate
+	ViewSelectVideos
+	ViewItemDetail
+	ViewPodcastForm
+	ViewConfirmDeletePodcast
+	ViewEditItem
+	ViewConfirmDeleteItems
 )
 
 type FatalErrorMsg struct {
@@ -41,13 +53,22 @@ type PodcastsLoadedMsg struct {
 }
 
 type UrlAddedMsg struct {
+	URL  string
 	Item api.Item
 	Err  error
 }
 
+type DuplicateCheckedMsg struct {
+	URL       string
+	Duplicate history.Duplicate
+	Found     bool
+	Err       error
+}
+
 type ItemsLoadedMsg struct {
-	Items []api.Item
-	Err   error
+	PodcastID string
+	Items     []api.Item
+	Err       error
 }
 
 type UsageLoadedMsg struct {
@@ -75,7 +96,7 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 	str := fmt.Sprintf("%d. %s", index+1, i)
 
 	if index == m.Index() {
-		fmt.Fprint(w, lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Render("> "+str))
+		fmt.Fprint(w, lipgloss.NewStyle().Foreground(AccentColor).Render("> "+str))
 	} else {
 		fmt.Fprint(w, "  "+str)
 	}
@@ -86,7 +107,13 @@ type Model struct {
 	HasAPIKey       bool
 	ApiKeyInput     textinput.Model
 	UrlInput        textinput.Model
+	SearchInput     textinput.Model
+	PodcastSearch   textinput.Model
+	PodcastForm     []textinput.Model
+	DeleteInput     textinput.Model
+	ItemForm        []textinput.Model
 	MainMenu        list.Model
+	ProfileList     list.Model
 	PodcastTable    table.Model
 	ItemsTable      table.Model
 	Podcasts        []api.Podcast
@@ -100,9 +127,59 @@ type Model struct {
 	Width           int
 	Height          int
 	Polling         bool
+
+	service      api.Service
+	profiles     Profiles
+	profileNames []string
+	history      *history.Store
+	pendingURL   string
+	duplicate    history.Duplicate
+	feeds        Feeds
+	recents      Recents
+
+	// podcastRows are the podcasts in picker order, after filtering.
+	// podcastCounts are the episode counts, kept for the session and
+	// updated whenever a podcast's items are loaded; countsLoading is set
+	// while the missing ones are fetched.
+	podcastRows   []api.Podcast
+	podcastCounts map[string]int
+	countsLoading bool
+
+	// editing is the podcast shown in ViewPodcastForm, or nil for a new
+	// one; deleting is the one ViewConfirmDeletePodcast asks about.
+	formFocus int
+	editing   *api.Podcast
+	deleting  api.Podcast
+
+	videoTitle    string
+	videos        []VideoChoice
+	videoSelected []bool
+	videoCursor   int
+
+	// itemRows are the items in table order, after itemFilter; detailItem
+	// is the one shown in ViewItemDetail.
+	itemFilter itemFilter
+	searching  bool
+	itemRows   []api.Item
+	detailItem api.Item
+	copy       func(string) error
+
+	// itemSelected holds the IDs of the items selected for a bulk action;
+	// editItem is the item in ViewEditItem and confirmItems the ones
+	// ViewConfirmDeleteItems asks about.
+	itemSelected map[string]bool
+	editItem     api.Item
+	confirmItems []api.Item
+
+	pollInterval time.Duration
+	columnWidths columnWidths
+
+	requestCtx     context.Context
+	cancelRequests context.CancelFunc
 }
 
-func InitialModel() Model {
+// InitialModel returns the TUI model backed by svc.
+func InitialModel(svc api.Service, opts ...Option) Model {
 	apiKeyInput := textinput.New()
 	apiKeyInput.Placeholder = "Enter your API key"
 	apiKeyInput.Focus()
@@ -114,6 +191,21 @@ func InitialModel() Model {
 	urlInput.CharLimit = 500
 	urlInput.Width = 80
 
+	searchInput := textinput.New()
+	searchInput.Prompt = "/"
+	searchInput.Placeholder = "Search titles"
+	searchInput.CharLimit = 100
+	searchInput.Width = 40
+
+	podcastSearch := textinput.New()
+	podcastSearch.Placeholder = "Type to filter"
+	podcastSearch.CharLimit = 100
+	podcastSearch.Width = 40
+
+	deleteInput := textinput.New()
+	deleteInput.CharLimit = 200
+	deleteInput.Width = 40
+
 	items := []list.Item{
 		menuItem("Add YouTube URL"),
 		menuItem("Set API Key"),
@@ -127,23 +219,43 @@ func InitialModel() Model {
 
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4"))
+	s.Style = lipgloss.NewStyle().Foreground(AccentColor)
 
 	prog := progress.New(progress.WithDefaultGradient())
 	prog.Width = 40
 
-	return Model{
-		State:       ViewSetAPIKey,
-		ApiKeyInput: apiKeyInput,
-		UrlInput:    urlInput,
-		MainMenu:    mainMenu,
-		Spinner:     s,
-		ProgressBar: prog,
+	m := Model{
+		State:         ViewSetAPIKey,
+		service:       svc,
+		ApiKeyInput:   apiKeyInput,
+		UrlInput:      urlInput,
+		SearchInput:   searchInput,
+		PodcastSearch: podcastSearch,
+		PodcastForm:   newPodcastForm(),
+		DeleteInput:   deleteInput,
+		ItemForm:      newItemForm(),
+		MainMenu:      mainMenu,
+		Spinner:       s,
+		ProgressBar:   prog,
+		PodcastTable:  newTable(true),
+		ItemsTable:    newTable(false),
+		feeds:         youtube.NewFeedClient(),
+		copy:          copyToClipboard,
+		pollInterval:  3 * time.Second,
+		columnWidths:  columnWidths{Title: 60, Status: 20, Created: 30},
+	}
+	for _, opt := range opts {
+		opt(&m)
 	}
+	if m.profiles != nil {
+		m.MainMenu.InsertItem(len(items), menuItem("Switch Profile"))
+	}
+	m.resetRequests()
+	return m
 }
 
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(CheckAPIKey, m.Spinner.Tick)
+	return tea.Batch(CheckAPIKey(m.service), m.Spinner.Tick)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -159,79 +271,220 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
 		m.Height = msg.Height
+		m.layout()
 
 	case ApiKeyCheckedMsg:
 		m.HasAPIKey = msg.HasKey
 		if msg.HasKey {
 			m.State = ViewMainMenu
-			return m, LoadUsage()
+			return m, LoadUsage(m.requestCtx, m.service)
 		} else {
 			m.State = ViewSetAPIKey
 			m.ApiKeyInput.Focus()
 		}
 
 	case UsageLoadedMsg:
+		if isCanceled(msg.Err) {
+			return m, nil
+		}
 		if msg.Err != nil {
-			m.Error = msg.Err.Error()
+			m.showAPIError(msg.Err)
 		} else {
 			m.Usage = msg.Usage
 		}
 
 	case PodcastsLoadedMsg:
+		if isCanceled(msg.Err) {
+			return m, nil
+		}
 		if msg.Err != nil {
-			m.Error = msg.Err.Error()
 			m.State = ViewMainMenu
+			m.showAPIError(msg.Err)
 		} else {
 			m.Podcasts = msg.Podcasts
 			m.Error = ""
+			if uncounted := m.uncountedPodcasts(); len(uncounted) > 0 {
+				m.countsLoading = true
+				cmds = append(cmds, LoadItemCounts(m.requestCtx, m.service, uncounted))
+			}
 			m.buildPodcastTable()
 			m.State = ViewSelectPodcast
 		}
 
+	case ItemCountsMsg:
+		if msg.Counts == nil {
+			return m, nil
+		}
+		m.countsLoading = false
+		if m.podcastCounts == nil {
+			m.podcastCounts = make(map[string]int)
+		}
+		for id, n := range msg.Counts {
+			m.podcastCounts[id] = n
+		}
+		if len(m.Podcasts) > 0 {
+			m.buildPodcastTable()
+		}
+
+	case PodcastSavedMsg:
+		if isCanceled(msg.Err) {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.Message = ""
+			m.showAPIError(msg.Err)
+			return m, nil
+		}
+		return m, m.podcastSaved(msg)
+
+	case PodcastDeletedMsg:
+		if isCanceled(msg.Err) {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.Message = ""
+			m.showAPIError(msg.Err)
+			return m, nil
+		}
+		return m, m.podcastDeleted(msg)
+
 	case UrlAddedMsg:
+		if isCanceled(msg.Err) {
+			return m, nil
+		}
 		if msg.Err != nil {
-			m.Error = msg.Err.Error()
+			m.showAPIError(msg.Err)
 		} else {
+			if m.history != nil {
+				// The history only improves duplicate detection; failing to
+				// save it shouldn't get in the way of the submission.
+				_ = m.history.Record(m.SelectedPodcast.ID, msg.URL, msg.Item.ID, time.Now())
+			}
 			m.Error = ""
 			m.State = ViewItemsTable
 			m.Polling = true
-			cmds = append(cmds, LoadItems(m.SelectedPodcast.ID))
+			cmds = append(cmds, LoadItems(m.requestCtx, m.service, m.SelectedPodcast.ID))
+		}
+
+	case DuplicateCheckedMsg:
+		if isCanceled(msg.Err) {
+			return m, nil
+		}
+		switch {
+		case msg.Err != nil:
+			m.showAPIError(msg.Err)
+		case msg.Found:
+			m.pendingURL = msg.URL
+			m.duplicate = msg.Duplicate
+			m.State = ViewConfirmDuplicate
+		default:
+			return m, AddURL(m.requestCtx, m.service, m.SelectedPodcast.ID, msg.URL)
+		}
+
+	case VideosLoadedMsg:
+		if isCanceled(msg.Err) {
+			return m, nil
+		}
+		m.Message = ""
+		switch {
+		case msg.Err != nil:
+			m.showAPIError(msg.Err)
+		case len(msg.Videos) == 0:
+			m.Error = "No videos found in " + msg.Title
+		default:
+			m.showVideos(msg)
+		}
+
+	case UrlsAddedMsg:
+		if isCanceled(msg.Err) {
+			return m, nil
+		}
+		m.Message = ""
+		if len(msg.Added) == 0 {
+			m.showAPIError(msg.Err)
+			return m, nil
+		}
+		if m.history != nil {
+			for i, url := range msg.Added {
+				_ = m.history.Record(m.SelectedPodcast.ID, url, msg.Items[i].ID, time.Now())
+			}
+		}
+		m.Error = ""
+		if msg.Failed > 0 {
+			m.showAPIError(msg.Err)
+			m.Error = fmt.Sprintf("%d of %d video(s) could not be added: %s", msg.Failed, msg.Failed+len(msg.Added), m.Error)
 		}
+		m.State = ViewItemsTable
+		m.Polling = true
+		cmds = append(cmds, LoadItems(m.requestCtx, m.service, m.SelectedPodcast.ID))
 
 	case ItemsLoadedMsg:
+		if isCanceled(msg.Err) {
+			return m, nil
+		}
+		if msg.Err == nil && m.podcastCounts != nil {
+			m.podcastCounts[msg.PodcastID] = len(msg.Items)
+		}
+		if m.SelectedPodcast == nil || m.SelectedPodcast.ID != msg.PodcastID {
+			// A late result for a podcast that is no longer shown.
+			return m, nil
+		}
 		if msg.Err != nil {
-			m.Error = msg.Err.Error()
 			m.Polling = false
+			m.showAPIError(msg.Err)
 		} else {
 			m.Items = msg.Items
 			m.buildItemsTable()
+			m.refreshItemDetail()
 
 			hasCreated := false
 			allSuccess := true
 			for _, item := range m.Items {
-				if item.Status == "CREATED" {
+				if item.Status == api.StatusCreated {
 					hasCreated = true
 					allSuccess = false
 					break
 				}
-				if item.Status != "SUCCESS" {
+				if item.Status != api.StatusSuccess {
 					allSuccess = false
 				}
 			}
 
 			if hasCreated && m.Polling {
-				cmds = append(cmds, tick())
+				cmds = append(cmds, m.tick())
 			} else {
 				m.Polling = false
 				if allSuccess && len(m.Items) > 0 {
-					cmds = append(cmds, LoadUsage())
+					cmds = append(cmds, LoadUsage(m.requestCtx, m.service))
 				}
 			}
 		}
 
+	case ItemRetriedMsg:
+		if isCanceled(msg.Err) {
+			return m, nil
+		}
+		m.Message = ""
+		if msg.Err != nil {
+			m.showAPIError(msg.Err)
+		} else {
+			m.Error = ""
+			cmds = append(cmds, m.retried(msg))
+		}
+
+	case ItemsChangedMsg:
+		return m, m.itemsChanged(msg)
+
+	case ClipboardMsg:
+		if msg.Err != nil {
+			m.Message = "Could not copy the " + msg.What + ": " + msg.Err.Error()
+		} else {
+			m.Message = "Copied the " + msg.What + " to the clipboard"
+		}
+
 	case TickMsg:
 		if m.Polling && m.SelectedPodcast != nil {
-			cmds = append(cmds, LoadItems(m.SelectedPodcast.ID))
+			cmds = append(cmds, LoadItems(m.requestCtx, m.service, m.SelectedPodcast.ID))
 		}
 
 	case tea.KeyMsg:
@@ -243,9 +496,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.State = ViewMainMenu
 					return m, nil
 				}
+				m.cancelRequests()
 				return m, tea.Quit
 			case "ctrl+d":
-				err := api.ClearApiKey()
+				err := m.service.ClearApiKey()
 				if err != nil {
 					m.Error = err.Error()
 				} else {
@@ -256,7 +510,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			case "enter":
 				if m.ApiKeyInput.Value() != "" {
-					err := api.SetApiKey(m.ApiKeyInput.Value())
+					err := m.service.SetApiKey(m.ApiKeyInput.Value())
 					if err != nil {
 						m.Error = err.Error()
 					} else {
@@ -264,7 +518,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.Message = "API key saved successfully!"
 						m.ApiKeyInput.SetValue("")
 						m.State = ViewMainMenu
-						return m, LoadUsage()
+						return m, LoadUsage(m.resetRequests(), m.service)
 					}
 				}
 				return m, nil
@@ -273,6 +527,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case ViewMainMenu:
 			switch msg.String() {
 			case "ctrl+c", "q":
+				m.cancelRequests()
 				return m, tea.Quit
 			case "enter":
 				selected := m.MainMenu.SelectedItem()
@@ -284,63 +539,136 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.Error = ""
 						m.Message = ""
 					case "Add YouTube URL":
-						m.State = ViewSelectPodcast
+						return m, m.openPodcastPicker()
+					case "Switch Profile":
+						m.buildProfileList()
+						m.State = ViewSelectProfile
 						m.Error = ""
 						m.Message = ""
-						return m, LoadPodcasts
+						return m, nil
 					}
 				}
 			}
 
-		case ViewSelectPodcast:
+		case ViewSelectProfile:
 			switch msg.String() {
 			case "ctrl+c", "q":
+				m.cancelRequests()
 				return m, tea.Quit
 			case "esc":
 				m.State = ViewMainMenu
+				m.Error = ""
 				return m, nil
 			case "enter":
-				if m.PodcastTable.Cursor() < len(m.Podcasts) {
-					m.SelectedPodcast = &m.Podcasts[m.PodcastTable.Cursor()]
-					m.State = ViewEnterURL
-					m.UrlInput.Focus()
-					m.UrlInput.SetValue("")
-					return m, nil
-				}
+				return m, m.switchProfile()
 			}
 
+		case ViewSelectPodcast:
+			return m, m.updatePodcastPicker(msg)
+
 		case ViewEnterURL:
+			// Only ctrl+c quits here; q is a valid character in a URL.
 			switch msg.String() {
-			case "ctrl+c", "q":
+			case "ctrl+c":
+				m.cancelRequests()
 				return m, tea.Quit
 			case "esc":
+				m.resetRequests()
 				m.State = ViewSelectPodcast
+				m.Message = ""
 				m.UrlInput.Blur()
+				m.PodcastSearch.Focus()
 				return m, nil
 			case "enter":
 				if m.UrlInput.Value() != "" && m.SelectedPodcast != nil {
-					url := m.UrlInput.Value()
+					url, err := youtube.Canonicalize(m.UrlInput.Value())
+					if list, listErr := youtube.ParseList(m.UrlInput.Value()); err != nil && listErr == nil {
+						m.Error = ""
+						m.Message = "Loading videos..."
+						m.UrlInput.SetValue("")
+						return m, LoadVideos(m.requestCtx, m.service, m.feeds, m.history, m.SelectedPodcast.ID, list)
+					}
+					if err != nil {
+						m.Error = err.Error()
+						return m, nil
+					}
+					m.Error = ""
+					m.Message = ""
 					m.UrlInput.SetValue("")
-					return m, AddURL(m.SelectedPodcast.ID, url)
+					return m, CheckDuplicate(m.requestCtx, m.service, m.history, m.SelectedPodcast.ID, url)
 				}
 			}
 
+		case ViewSelectVideos:
+			return m, m.updateSelectVideos(msg)
+
+		case ViewItemDetail:
+			return m, m.updateItemDetail(msg)
+
+		case ViewPodcastForm:
+			return m, m.updatePodcastForm(msg)
+
+		case ViewConfirmDeletePodcast:
+			return m, m.updateDeletePodcast(msg)
+
+		case ViewEditItem:
+			return m, m.updateEditItem(msg)
+
+		case ViewConfirmDeleteItems:
+			return m, m.updateConfirmDeleteItems(msg)
+
+		case ViewConfirmDuplicate:
+			switch msg.String() {
+			case "ctrl+c":
+				m.cancelRequests()
+				return m, tea.Quit
+			case "y", "enter":
+				m.State = ViewEnterURL
+				return m, AddURL(m.requestCtx, m.service, m.SelectedPodcast.ID, m.pendingURL)
+			case "n", "esc":
+				m.State = ViewEnterURL
+				m.Message = "Skipped " + m.pendingURL
+				return m, nil
+			}
+			return m, nil
+
 		case ViewItemsTable:
+			if m.searching {
+				return m, m.updateItemSearch(msg)
+			}
+			if cmd, ok := m.updateManageItems(msg); ok {
+				return m, cmd
+			}
+			if m.updateItemsFilter(msg) {
+				return m, nil
+			}
 			switch msg.String() {
 			case "ctrl+c", "q":
 				m.Polling = false
+				m.cancelRequests()
 				return m, tea.Quit
 			case "a":
+				m.resetRequests()
+				m.Message = ""
 				m.State = ViewEnterURL
 				m.UrlInput.Focus()
 				m.UrlInput.SetValue("")
 				m.Polling = false
 				return m, nil
+			case "enter":
+				m.openItemDetail()
+				return m, nil
+			case "r":
+				if item, ok := m.selectedItem(); ok {
+					return m, m.retryItem(item)
+				}
+				return m, nil
 			case "m":
 				m.State = ViewMainMenu
+				m.Message = ""
 				m.Polling = false
 				m.SelectedPodcast = nil
-				return m, LoadUsage()
+				return m, LoadUsage(m.resetRequests(), m.service)
 			}
 		}
 	}
@@ -352,8 +680,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case ViewMainMenu:
 		m.MainMenu, cmd = m.MainMenu.Update(msg)
 		cmds = append(cmds, cmd)
+	case ViewSelectProfile:
+		m.ProfileList, cmd = m.ProfileList.Update(msg)
+		cmds = append(cmds, cmd)
 	case ViewSelectPodcast:
-		m.PodcastTable, cmd = m.PodcastTable.Update(msg)
+		m.PodcastSearch, cmd = m.PodcastSearch.Update(msg)
 		cmds = append(cmds, cmd)
 	case ViewEnterURL:
 		m.UrlInput, cmd = m.UrlInput.Update(msg)
@@ -405,6 +736,12 @@ func (m Model) View() string {
 		s.WriteString(m.MainMenu.View())
 		s.WriteString("\n")
 
+		if m.profiles != nil {
+			s.WriteString("\n")
+			s.WriteString(MutedStyle.Render("Profile: " + m.profiles.Active()))
+			s.WriteString("\n")
+		}
+
 		if m.Usage != nil {
 			s.WriteString("\n")
 			usagePercent := 0.0
@@ -412,10 +749,10 @@ func (m Model) View() string {
 				usagePercent = float64(m.Usage.Usage) / float64(m.Usage.Limit)
 			}
 			usageText := fmt.Sprintf("Usage: %s / %s",
-				formatBytes(m.Usage.Usage),
-				formatBytes(m.Usage.Limit),
+				output.FormatBytes(m.Usage.Usage),
+				output.FormatBytes(m.Usage.Limit),
 			)
-			s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Render(usageText))
+			s.WriteString(MutedStyle.Render(usageText))
 			s.WriteString("\n")
 			s.WriteString(m.ProgressBar.ViewAs(usagePercent))
 			s.WriteString("\n")
@@ -426,45 +763,100 @@ func (m Model) View() string {
 
 		s.WriteString(HelpStyle.Render("↑/↓: Navigate • Enter: Select • q: Quit"))
 
-	case ViewSelectPodcast:
-		s.WriteString(TitleStyle.Render("Select a Podcast"))
-		s.WriteString("\n")
-		if len(m.Podcasts) == 0 {
-			s.WriteString("No podcasts found.\n")
-		} else {
-			s.WriteString(m.PodcastTable.View())
-		}
+	case ViewSelectProfile:
+		s.WriteString(m.ProfileList.View())
 		s.WriteString("\n")
 		if m.Error != "" {
 			s.WriteString(ErrorStyle.Render("Error: " + m.Error))
 			s.WriteString("\n")
 		}
-		s.WriteString(HelpStyle.Render("↑/↓: Navigate • Enter: Select • Esc: Back • q: Quit"))
+		s.WriteString(HelpStyle.Render("↑/↓: Navigate • Enter: Switch • Esc: Back • q: Quit"))
+
+	case ViewSelectPodcast:
+		s.WriteString(m.viewPodcastPicker())
 
 	case ViewEnterURL:
 		s.WriteString(TitleStyle.Render(fmt.Sprintf("Add URL to: %s", m.SelectedPodcast.Title)))
 		s.WriteString("\n")
+		if m.Message != "" {
+			s.WriteString(MutedStyle.Render(m.Message))
+			s.WriteString("\n")
+		}
 		s.WriteString(m.UrlInput.View())
 		s.WriteString("\n")
 		if m.Error != "" {
 			s.WriteString(ErrorStyle.Render("Error: " + m.Error))
 			s.WriteString("\n")
 		}
-		s.WriteString(HelpStyle.Render("Press Enter to add URL • Esc: Back • q: Quit"))
+		s.WriteString(HelpStyle.Render("Press Enter to add URL • Esc: Back • Ctrl+c: Quit"))
+
+	case ViewSelectVideos:
+		s.WriteString(m.viewSelectVideos())
+
+	case ViewItemDetail:
+		s.WriteString(m.viewItemDetail())
+
+	case ViewPodcastForm:
+		s.WriteString(m.viewPodcastForm())
+
+	case ViewConfirmDeletePodcast:
+		s.WriteString(m.viewDeletePodcast())
+
+	case ViewEditItem:
+		s.WriteString(m.viewEditItem())
+
+	case ViewConfirmDeleteItems:
+		s.WriteString(m.viewConfirmDeleteItems())
+
+	case ViewConfirmDuplicate:
+		s.WriteString(TitleStyle.Render(fmt.Sprintf("Add URL to: %s", m.SelectedPodcast.Title)))
+		s.WriteString("\n")
+		s.WriteString(ErrorStyle.Render("Already " + duplicateText(m.duplicate) + " — add anyway?"))
+		s.WriteString("\n")
+		s.WriteString(MutedStyle.Render(m.pendingURL))
+		s.WriteString("\n")
+		s.WriteString(HelpStyle.Render("y: Add anyway • n/Esc: Cancel • Ctrl+c: Quit"))
 
 	case ViewItemsTable:
 		s.WriteString(TitleStyle.Render(fmt.Sprintf("Items for: %s", m.SelectedPodcast.Title)))
 		s.WriteString("\n")
+		if m.searching || m.itemFilter.query != "" {
+			s.WriteString(m.SearchInput.View())
+			s.WriteString("\n")
+		}
 		s.WriteString(m.ItemsTable.View())
 		s.WriteString("\n")
+		if len(m.itemRows) == 0 && len(m.Items) > 0 {
+			s.WriteString(MutedStyle.Render("No items match. Esc clears the filters."))
+			s.WriteString("\n")
+		}
 		if m.Error != "" {
 			s.WriteString(ErrorStyle.Render("Error: " + m.Error))
 			s.WriteString("\n")
 		}
+		if m.Message != "" {
+			s.WriteString(SuccessStyle.Render(m.Message))
+			s.WriteString("\n")
+		}
+		status := m.itemFilter.summary(len(m.itemRows), len(m.Items))
+		if len(m.itemSelected) > 0 {
+			status = strings.TrimPrefix(status+fmt.Sprintf(" • %d selected", len(m.itemSelected)), " • ")
+		}
 		if m.Polling {
-			s.WriteString(HelpStyle.Render("Polling for updates... • a: Add another URL • m: Main menu • q: Quit"))
+			status = strings.TrimPrefix(status+" • Polling for updates...", " • ")
+		}
+		if status != "" {
+			s.WriteString(MutedStyle.Render(status))
+			s.WriteString("\n")
+		}
+		if m.searching {
+			s.WriteString(HelpStyle.Render("Type to search • Enter: Done • Esc: Clear search"))
 		} else {
-			s.WriteString(HelpStyle.Render("a: Add another URL • m: Main menu • q: Quit"))
+			s.WriteString(HelpStyle.Render("Enter: Details • r: Retry • a: Add another URL • m: Main menu • q: Quit"))
+			s.WriteString("\n")
+			s.WriteString(MutedStyle.Render("/: Search • 1/2/3: Processing/Success/Error • o/O: Sort, reverse • Esc: Clear"))
+			s.WriteString("\n")
+			s.WriteString(MutedStyle.Render("Space: Select • *: All • e: Edit • h: Hide/unhide • d: Delete"))
 		}
 	}
 
@@ -0,0 +1,962 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/api/fake"
+	"github.com/lsherman98/yt-rss-cli/history"
+	"github.com/lsherman98/yt-rss-cli/youtube"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// sizes are the terminal sizes every snapshot is taken at.
+var sizes = []struct{ width, height int }{
+	{80, 24},
+	{120, 40},
+}
+
+// timerDelay replaces the delay of every timer in the model, so that timer
+// commands return at once and the harness can drop them by message type.
+const timerDelay = time.Nanosecond
+
+// cmdDeadline fails a test whose command never returns instead of letting
+// it hang.
+const cmdDeadline = 5 * time.Second
+
+// failingVideo is a video ID the fake reports as failed.
+const failingVideo = "FAILFAILFAI"
+
+var epoch = time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+func TestMain(m *testing.M) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	time.Local = time.UTC
+	os.Exit(m.Run())
+}
+
+// harness drives a Model the way tea.Program would, but synchronously:
+// every command is run to completion and its message fed back into Update
+// before the next input is sent.
+type harness struct {
+	t     *testing.T
+	model tea.Model
+	svc   *fake.Service
+	now   time.Time
+	size  string
+	quit  bool
+}
+
+func newHarness(t *testing.T, svc *fake.Service, width, height int, opts ...Option) *harness {
+	t.Helper()
+	h := &harness{t: t, svc: svc, now: epoch, size: sizeName(width, height)}
+	svc.Now = func() time.Time { return h.now }
+
+	// Polling ticks are sent explicitly with h.tick; the timer's own ticks
+	// are dropped in run.
+	opts = append([]Option{WithPollInterval(timerDelay)}, opts...)
+	m := InitialModel(svc, opts...)
+	m.Spinner.Spinner.FPS = timerDelay
+	// A blinking cursor would send a blink message after every keystroke.
+	m.ApiKeyInput.Cursor.SetMode(cursor.CursorStatic)
+	m.UrlInput.Cursor.SetMode(cursor.CursorStatic)
+	m.SearchInput.Cursor.SetMode(cursor.CursorStatic)
+	m.PodcastSearch.Cursor.SetMode(cursor.CursorStatic)
+	m.DeleteInput.Cursor.SetMode(cursor.CursorStatic)
+	for i := range m.PodcastForm {
+		m.PodcastForm[i].Cursor.SetMode(cursor.CursorStatic)
+	}
+	for i := range m.ItemForm {
+		m.ItemForm[i].Cursor.SetMode(cursor.CursorStatic)
+	}
+	h.model = m
+	h.run(h.model.Init())
+	h.send(tea.WindowSizeMsg{Width: width, Height: height})
+	return h
+}
+
+// newDemoService returns a fake with two podcasts and a few episodes at
+// fixed times.
+func newDemoService(apiKey string) *fake.Service {
+	svc := fake.New(apiKey)
+	svc.ProcessingDelay = time.Minute
+	svc.FailURL = func(url string) string {
+		if strings.Contains(url, failingVideo) {
+			return "video is unavailable"
+		}
+		return ""
+	}
+	talks := svc.AddPodcast("Conference Talks")
+	svc.AddPodcast("Interviews")
+	svc.AddItem(talks.ID, api.Item{Status: api.StatusSuccess, Title: "Simple Made Easy"}, "https://youtu.be/SxdOUGdseq4", epoch.Add(-48*time.Hour))
+	svc.AddItem(talks.ID, api.Item{Status: api.StatusError, Error: "video is private"}, "https://youtu.be/private", epoch.Add(-24*time.Hour))
+	return svc
+}
+
+func sizeName(width, height int) string {
+	return fmt.Sprintf("%dx%d", width, height)
+}
+
+func (h *harness) send(msg tea.Msg) {
+	h.t.Helper()
+	if h.quit {
+		h.t.Fatalf("sent %T after the program quit", msg)
+	}
+	var cmd tea.Cmd
+	h.model, cmd = h.model.Update(msg)
+	h.run(cmd)
+}
+
+func (h *harness) run(cmd tea.Cmd) {
+	h.t.Helper()
+	queue := []tea.Cmd{cmd}
+	for len(queue) > 0 && !h.quit {
+		cmd, queue = queue[0], queue[1:]
+		if cmd == nil {
+			continue
+		}
+
+		msg := h.runCmd(cmd)
+		if msg == nil {
+			continue
+		}
+		switch msg := msg.(type) {
+		case tea.BatchMsg:
+			queue = append(queue, msg...)
+		case tea.QuitMsg:
+			h.quit = true
+		case spinner.TickMsg, cursor.BlinkMsg, TickMsg:
+			// Timers: animation and polling would otherwise loop forever.
+		default:
+			var next tea.Cmd
+			h.model, next = h.model.Update(msg)
+			queue = append(queue, next)
+		}
+	}
+}
+
+func (h *harness) runCmd(cmd tea.Cmd) tea.Msg {
+	h.t.Helper()
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+	select {
+	case msg := <-done:
+		return msg
+	case <-time.After(cmdDeadline):
+		h.t.Fatalf("a command did not return within %s", cmdDeadline)
+		return nil
+	}
+}
+
+func (h *harness) typeText(s string) {
+	h.t.Helper()
+	for _, r := range s {
+		h.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func (h *harness) press(keys ...tea.KeyType) {
+	h.t.Helper()
+	for _, k := range keys {
+		h.send(tea.KeyMsg{Type: k})
+	}
+}
+
+// tick advances the fake clock and delivers a polling tick.
+func (h *harness) tick(d time.Duration) {
+	h.t.Helper()
+	h.now = h.now.Add(d)
+	h.send(TickMsg(h.now))
+}
+
+func (h *harness) state() ViewState {
+	return h.model.(Model).State
+}
+
+func (h *harness) expectState(want ViewState) {
+	h.t.Helper()
+	if got := h.state(); got != want {
+		h.t.Fatalf("state = %v, want %v\n%s", got, want, h.model.View())
+	}
+}
+
+// snapshot compares the current view to testdata/<test>/<name>_<size>.golden.
+func (h *harness) snapshot(name string) {
+	h.t.Helper()
+	got := h.model.View() + "\n"
+	path := filepath.Join("testdata", h.t.Name(), name+"_"+h.size+".golden")
+	if i := strings.Index(h.t.Name(), "/"); i >= 0 {
+		// Subtests share the parent's directory; the size is in the file name.
+		path = filepath.Join("testdata", h.t.Name()[:i], name+"_"+h.size+".golden")
+	}
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			h.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			h.t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		h.t.Fatalf("%v (run go test ./ui -update to create it)", err)
+	}
+	if got != string(want) {
+		h.t.Errorf("%s does not match the view (run go test ./ui -update if the change is intended)\n--- got ---\n%s--- want ---\n%s", path, got, want)
+	}
+}
+
+func eachSize(t *testing.T, fn func(t *testing.T, width, height int)) {
+	for _, size := range sizes {
+		t.Run(sizeName(size.width, size.height), func(t *testing.T) {
+			fn(t, size.width, size.height)
+		})
+	}
+}
+
+func TestSetAPIKey(t *testing.T) {
+	eachSize(t, func(t *testing.T, width, height int) {
+		h := newHarness(t, newDemoService(""), width, height)
+		h.expectState(ViewSetAPIKey)
+		h.snapshot("empty")
+
+		h.typeText("secret-key")
+		h.snapshot("typed")
+
+		h.press(tea.KeyEnter)
+		h.expectState(ViewMainMenu)
+		h.snapshot("saved")
+		if key, _ := h.svc.GetApiKey(); key != "secret-key" {
+			t.Errorf("stored key = %q, want %q", key, "secret-key")
+		}
+	})
+}
+
+func TestSetAPIKeyEscQuitsWithoutKey(t *testing.T) {
+	h := newHarness(t, newDemoService(""), 80, 24)
+	h.press(tea.KeyEsc)
+	if !h.quit {
+		t.Fatal("esc without an API key did not quit")
+	}
+}
+
+func TestMainMenu(t *testing.T) {
+	eachSize(t, func(t *testing.T, width, height int) {
+		h := newHarness(t, newDemoService("key"), width, height)
+		h.expectState(ViewMainMenu)
+		h.snapshot("usage")
+
+		h.press(tea.KeyDown)
+		h.snapshot("second")
+
+		h.press(tea.KeyEnter)
+		h.expectState(ViewSetAPIKey)
+		h.press(tea.KeyEsc)
+		h.expectState(ViewMainMenu)
+	})
+}
+
+func TestSelectPodcast(t *testing.T) {
+	eachSize(t, func(t *testing.T, width, height int) {
+		h := newHarness(t, newDemoService("key"), width, height)
+		h.press(tea.KeyEnter)
+		h.expectState(ViewSelectPodcast)
+		h.snapshot("list")
+
+		h.press(tea.KeyDown)
+		h.snapshot("second")
+
+		h.press(tea.KeyEsc)
+		h.expectState(ViewMainMenu)
+	})
+}
+
+func TestPodcastPicker(t *testing.T) {
+	eachSize(t, func(t *testing.T, width, height int) {
+		recent, err := history.OpenRecent(filepath.Join(t.TempDir(), "recent.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		recent.Use("pod002")
+
+		svc := newDemoService("key")
+		for _, title := range []string{"Lectures", "Book Club", "Conference Highlights"} {
+			svc.AddPodcast(title)
+		}
+		h := newHarness(t, svc, width, height, WithRecents(recent))
+		h.press(tea.KeyEnter)
+		h.expectState(ViewSelectPodcast)
+		h.snapshot("recent")
+
+		h.typeText("conf")
+		h.snapshot("filtered")
+
+		h.press(tea.KeyEnter)
+		h.expectState(ViewEnterURL)
+		if got := h.model.(Model).SelectedPodcast.Title; got != "Conference Talks" {
+			t.Errorf("selected %q, want Conference Talks", got)
+		}
+		if got, want := recent.IDs(), []string{"pod001", "pod002"}; fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("recent = %v, want %v", got, want)
+		}
+
+		h.press(tea.KeyEsc, tea.KeyEsc, tea.KeyEsc)
+		h.expectState(ViewMainMenu)
+		h.press(tea.KeyEnter)
+		h.snapshot("reopened")
+	})
+}
+
+func TestPodcastPickerCachesCounts(t *testing.T) {
+	svc := newDemoService("key")
+	h := newHarness(t, svc, 100, 30)
+	counts := func() map[string]int { return h.model.(Model).podcastCounts }
+
+	h.press(tea.KeyEnter)
+	h.expectState(ViewSelectPodcast)
+	if got := counts(); got["pod001"] != 2 || got["pod002"] != 0 {
+		t.Fatalf("counts = %v, want 2 and 0", got)
+	}
+
+	// Reopening the picker uses the counts already loaded, so an item
+	// added elsewhere shows up only once the podcast's items are loaded.
+	svc.AddItem("pod001", api.Item{Status: api.StatusSuccess}, "https://youtu.be/dQw4w9WgXcQ", epoch)
+	h.press(tea.KeyEsc)
+	h.press(tea.KeyEnter)
+	h.expectState(ViewSelectPodcast)
+	if got := counts()["pod001"]; got != 2 {
+		t.Errorf("count after reopening = %d, want the cached 2", got)
+	}
+
+	h.press(tea.KeyEnter)
+	items, _ := svc.GetPodcastItems(context.Background(), "pod001")
+	h.send(ItemsLoadedMsg{PodcastID: "pod001", Items: items})
+	if got := counts()["pod001"]; got != 3 {
+		t.Errorf("count after loading the items = %d, want 3", got)
+	}
+
+	// A late result for another podcast counts for that podcast only and
+	// isn't shown; after going back to the menu nothing is selected.
+	h.send(ItemsLoadedMsg{PodcastID: "pod002", Items: items[:1]})
+	if got := counts(); got["pod001"] != 3 || got["pod002"] != 1 {
+		t.Errorf("counts = %v, want 3 and 1", got)
+	}
+	if got := len(h.model.(Model).Items); got != 3 {
+		t.Errorf("%d items shown, want the 3 of the selected podcast", got)
+	}
+	h.press(tea.KeyEsc, tea.KeyEsc)
+	h.expectState(ViewMainMenu)
+	h.send(ItemsLoadedMsg{PodcastID: "pod001", Items: items[:2]})
+	h.expectState(ViewMainMenu)
+	if got := counts()["pod001"]; got != 2 {
+		t.Errorf("count after a late result = %d, want 2", got)
+	}
+}
+
+func TestManagePodcasts(t *testing.T) {
+	eachSize(t, func(t *testing.T, width, height int) {
+		svc := newDemoService("key")
+		h := newHarness(t, svc, width, height)
+		h.press(tea.KeyEnter)
+
+		h.press(tea.KeyCtrlN)
+		h.expectState(ViewPodcastForm)
+		h.press(tea.KeyEnter)
+		h.snapshot("untitled")
+
+		h.typeText("Book Club")
+		h.press(tea.KeyTab, tea.KeyTab)
+		h.typeText("Jane Doe")
+		h.press(tea.KeyTab)
+		h.typeText("ftp://cover")
+		h.press(tea.KeyEnter)
+		h.expectState(ViewPodcastForm)
+		h.snapshot("invalid")
+
+		for range len("ftp://cover") {
+			h.press(tea.KeyBackspace)
+		}
+		h.press(tea.KeyEnter)
+		h.expectState(ViewSelectPodcast)
+		h.snapshot("created")
+
+		h.press(tea.KeyDown, tea.KeyDown, tea.KeyCtrlE)
+		h.expectState(ViewPodcastForm)
+		h.snapshot("edit")
+		h.typeText(" Weekly")
+		h.press(tea.KeyEnter)
+		h.expectState(ViewSelectPodcast)
+		podcasts, _ := svc.ListPodcasts(context.Background())
+		if got := podcasts[2]; got.Title != "Book Club Weekly" || got.Author != "Jane Doe" {
+			t.Errorf("edited podcast = %+v, want Book Club Weekly by Jane Doe", got)
+		}
+
+		h.press(tea.KeyUp, tea.KeyUp, tea.KeyCtrlD)
+		h.expectState(ViewConfirmDeletePodcast)
+		h.typeText("conference talks")
+		h.press(tea.KeyEnter)
+		h.expectState(ViewConfirmDeletePodcast)
+		h.snapshot("mismatch")
+
+		h.press(tea.KeyEsc)
+		h.expectState(ViewSelectPodcast)
+		h.press(tea.KeyCtrlD)
+		h.typeText("Conference Talks")
+		h.press(tea.KeyEnter)
+		h.expectState(ViewSelectPodcast)
+		h.snapshot("deleted")
+		if podcasts, _ := svc.ListPodcasts(context.Background()); len(podcasts) != 2 {
+			t.Errorf("%d podcasts left, want 2", len(podcasts))
+		}
+	})
+}
+
+func TestEnterURL(t *testing.T) {
+	eachSize(t, func(t *testing.T, width, height int) {
+		h := newHarness(t, newDemoService("key"), width, height)
+		h.press(tea.KeyEnter, tea.KeyEnter)
+		h.expectState(ViewEnterURL)
+		h.snapshot("empty")
+
+		// q must be typed, not quit the program.
+		h.typeText("https://youtu.be/quietquietq?si=q")
+		if h.quit {
+			t.Fatal("typing q in the URL input quit the program")
+		}
+		h.snapshot("typed")
+
+		h.press(tea.KeyEsc)
+		h.expectState(ViewSelectPodcast)
+	})
+}
+
+func TestEnterURLRejectsInvalid(t *testing.T) {
+	svc := newDemoService("key")
+	h := newHarness(t, svc, 80, 24)
+	h.press(tea.KeyEnter, tea.KeyEnter)
+	h.typeText("https://vimeo.com/12345")
+	h.press(tea.KeyEnter)
+	h.expectState(ViewEnterURL)
+	h.snapshot("invalid")
+
+	if items, _ := svc.GetPodcastItems(context.Background(), "pod001"); len(items) != 2 {
+		t.Errorf("invalid URL was submitted: %d items, want 2", len(items))
+	}
+}
+
+func TestItemsTable(t *testing.T) {
+	eachSize(t, func(t *testing.T, width, height int) {
+		h := newHarness(t, newDemoService("key"), width, height)
+		h.press(tea.KeyEnter, tea.KeyEnter)
+		h.typeText("https://youtu.be/NEWVIDEO123")
+		h.press(tea.KeyEnter)
+		h.expectState(ViewItemsTable)
+		h.snapshot("processing")
+
+		h.tick(time.Minute)
+		h.snapshot("done")
+
+		h.typeText("a")
+		h.expectState(ViewEnterURL)
+		h.typeText("https://youtu.be/" + failingVideo)
+		h.press(tea.KeyEnter)
+		h.tick(time.Minute)
+		h.snapshot("failed")
+
+		h.typeText("m")
+		h.expectState(ViewMainMenu)
+	})
+}
+
+func TestItemDetail(t *testing.T) {
+	eachSize(t, func(t *testing.T, width, height int) {
+		svc := newDemoService("key")
+		svc.AddItem("pod001", api.Item{
+			Status: api.StatusError,
+			Title:  "Rich Hickey Keynote",
+			Error:  "download failed: HTTP Error 403: Forbidden (the video may be age-restricted or blocked in the server's region)",
+			Extra:  map[string]any{"duration": 3120.0, "job_id": "job_42"},
+		}, "https://www.youtube.com/watch?v=KEYNOTE0001", epoch.Add(-time.Hour))
+
+		var copied []string
+		h := newHarness(t, svc, width, height, WithClipboard(func(text string) error {
+			copied = append(copied, text)
+			return nil
+		}))
+		h.press(tea.KeyEnter, tea.KeyEnter)
+		h.typeText("https://youtu.be/NEWVIDEO123")
+		h.press(tea.KeyEnter)
+		h.tick(time.Minute)
+
+		h.press(tea.KeyDown, tea.KeyEnter)
+		h.expectState(ViewItemDetail)
+		h.snapshot("error")
+
+		h.typeText("c")
+		h.typeText("u")
+		want := []string{
+			"download failed: HTTP Error 403: Forbidden (the video may be age-restricted or blocked in the server's region)",
+			"https://www.youtube.com/watch?v=KEYNOTE0001",
+		}
+		if fmt.Sprint(copied) != fmt.Sprint(want) {
+			t.Errorf("copied %q, want %q", copied, want)
+		}
+		h.snapshot("copied")
+
+		h.press(tea.KeyEsc)
+		h.expectState(ViewItemsTable)
+		if got := h.model.(Model).ItemsTable.Cursor(); got != 1 {
+			t.Errorf("cursor = %d after returning from details, want 1", got)
+		}
+	})
+}
+
+func TestManageItems(t *testing.T) {
+	eachSize(t, func(t *testing.T, width, height int) {
+		store, err := history.Open(filepath.Join(t.TempDir(), "history.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc := newDemoService("key")
+		h := newHarness(t, svc, width, height, WithHistory(store))
+		h.press(tea.KeyEnter, tea.KeyEnter)
+		h.typeText("https://youtu.be/NEWVIDEO123")
+		h.press(tea.KeyEnter)
+		h.tick(time.Minute)
+
+		h.press(tea.KeySpace, tea.KeySpace)
+		h.snapshot("selected")
+		h.typeText("h")
+		h.snapshot("hidden")
+		items, _ := svc.GetPodcastItems(context.Background(), "pod001")
+		if !items[0].Hidden || !items[1].Hidden || items[2].Hidden {
+			t.Errorf("hidden = %v %v %v, want the first two", items[0].Hidden, items[1].Hidden, items[2].Hidden)
+		}
+
+		h.typeText("e")
+		h.expectState(ViewEditItem)
+		h.snapshot("edit")
+		h.press(tea.KeyTab, tea.KeyTab)
+		h.typeText("soon")
+		h.press(tea.KeyEnter)
+		h.expectState(ViewEditItem)
+		h.snapshot("invalid")
+		h.press(tea.KeyBackspace, tea.KeyBackspace, tea.KeyBackspace, tea.KeyBackspace)
+		h.typeText("2025-12-01")
+		h.press(tea.KeyEnter)
+		h.expectState(ViewItemsTable)
+		items, _ = svc.GetPodcastItems(context.Background(), "pod001")
+		if got := items[2].Published; got != "2025-12-01T00:00:00Z" {
+			t.Errorf("published = %q, want 2025-12-01T00:00:00Z", got)
+		}
+
+		h.typeText("*d")
+		h.expectState(ViewConfirmDeleteItems)
+		h.snapshot("confirm")
+		h.typeText("n")
+		h.press(tea.KeyEsc)
+		h.expectState(ViewItemsTable)
+
+		h.press(tea.KeyUp, tea.KeyUp)
+		h.typeText("dy")
+		h.expectState(ViewItemsTable)
+		h.snapshot("deleted")
+		if items, _ := svc.GetPodcastItems(context.Background(), "pod001"); len(items) != 2 {
+			t.Errorf("%d items left, want 2", len(items))
+		}
+		if _, ok := store.Lookup("pod001", "NEWVIDEO123"); ok {
+			t.Error("the deleted video is still in the history")
+		}
+	})
+}
+
+func TestManageItemsWithoutIDs(t *testing.T) {
+	svc := newDemoService("key")
+	svc.OmitItemIDs = true
+	h := newHarness(t, svc, 100, 30)
+	h.press(tea.KeyEnter, tea.KeyEnter)
+	h.typeText("https://youtu.be/NEWVIDEO123")
+	h.press(tea.KeyEnter)
+	h.tick(time.Minute)
+	h.expectState(ViewItemsTable)
+
+	for _, key := range []string{" ", "*", "e", "d", "h"} {
+		h.model = clearMessages(h.model.(Model))
+		h.typeText(key)
+		m := h.model.(Model)
+		if m.State != ViewItemsTable || len(m.itemSelected) != 0 || m.Error == "" {
+			t.Errorf("%q: state %v, %d selected, error %q; want an error and nothing selected", key, m.State, len(m.itemSelected), m.Error)
+		}
+	}
+	svc.OmitItemIDs = false
+	items, _ := svc.GetPodcastItems(context.Background(), "pod001")
+	if len(items) != 3 || slices.ContainsFunc(items, func(item api.Item) bool { return item.Hidden }) {
+		t.Errorf("items changed: %+v", items)
+	}
+}
+
+func clearMessages(m Model) Model {
+	m.Error, m.Message = "", ""
+	return m
+}
+
+func TestItemsChangedAfterLeaving(t *testing.T) {
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Record("pod001", "https://youtu.be/dQw4w9WgXcQ", "item1", epoch)
+	h := newHarness(t, newDemoService("key"), 100, 30, WithHistory(store))
+	h.press(tea.KeyEnter)
+	h.expectState(ViewSelectPodcast)
+
+	// The second item failed, then the user left before the third could be
+	// deleted. The late message must not pull them back to the items.
+	errs := []error{nil, errors.New("server error"), context.Canceled}
+	msg := changeItems([]api.Item{{ID: "item1"}, {ID: "item2"}, {ID: "item3"}}, func(api.Item) error {
+		err := errs[0]
+		errs = errs[1:]
+		return err
+	})
+	if !isCanceled(msg.Err) || msg.Failed != 2 || len(msg.Changed) != 1 {
+		t.Fatalf("changeItems = %+v, want one change and the cancellation", msg)
+	}
+	msg.Done, msg.Deleted, msg.PodcastID = "Deleted", true, "pod001"
+	h.send(msg)
+	h.expectState(ViewSelectPodcast)
+	if m := h.model.(Model); m.Message != "" || m.Error != "" {
+		t.Errorf("message %q, error %q; want neither", m.Message, m.Error)
+	}
+	if _, ok := store.Lookup("pod001", "dQw4w9WgXcQ"); ok {
+		t.Error("the video deleted before leaving is still in the history")
+	}
+
+	// A change that succeeded is dropped too once no podcast, or another
+	// one, is selected.
+	saved := ItemsChangedMsg{Done: "Saved", PodcastID: "pod001", Changed: []api.Item{{ID: "item2"}}}
+	h.send(saved)
+	h.expectState(ViewSelectPodcast)
+	h.press(tea.KeyDown, tea.KeyEnter)
+	h.expectState(ViewEnterURL)
+	h.send(saved)
+	h.expectState(ViewEnterURL)
+	if m := h.model.(Model); m.Message != "" {
+		t.Errorf("message %q for a change to another podcast", m.Message)
+	}
+}
+
+func TestItemsTableScrolls(t *testing.T) {
+	eachSize(t, func(t *testing.T, width, height int) {
+		svc := newDemoService("key")
+		for i := range 200 {
+			svc.AddItem("pod001", api.Item{Status: api.StatusSuccess, Title: fmt.Sprintf("Episode %03d with a title long enough to need truncating on narrow terminals", i)},
+				"", epoch.Add(-time.Duration(i+100)*time.Hour))
+		}
+		h := newHarness(t, svc, width, height)
+		h.press(tea.KeyEnter, tea.KeyEnter)
+		h.typeText("https://youtu.be/NEWVIDEO123")
+		h.press(tea.KeyEnter)
+		h.tick(time.Minute)
+
+		for range 10 {
+			h.press(tea.KeyPgDown)
+		}
+		h.tick(time.Minute)
+		h.snapshot("scrolled")
+
+		view := h.model.View()
+		if got := lipgloss.Height(view); got > height {
+			t.Errorf("view is %d lines, taller than the %d line terminal", got, height)
+		}
+		selected, _ := h.model.(Model).selectedItem()
+		if !strings.Contains(view, selected.Title[:12]) {
+			t.Errorf("selected row %q is not visible:\n%s", selected.Title, view)
+		}
+	})
+}
+
+func TestFilterItems(t *testing.T) {
+	eachSize(t, func(t *testing.T, width, height int) {
+		svc := newDemoService("key")
+		svc.AddItem("pod001", api.Item{Status: api.StatusSuccess, Title: "Hammock Driven Development"}, "", epoch.Add(-72*time.Hour))
+		svc.AddItem("pod001", api.Item{Status: api.StatusError, Title: "The Value of Values", Error: "timeout"}, "", epoch.Add(-96*time.Hour))
+		h := newHarness(t, svc, width, height)
+		h.press(tea.KeyEnter, tea.KeyEnter)
+		h.typeText("https://youtu.be/NEWVIDEO123")
+		h.press(tea.KeyEnter)
+		h.tick(time.Minute)
+
+		h.typeText("/")
+		h.typeText("hdd")
+		h.snapshot("searching")
+		h.press(tea.KeyEnter)
+		if got := len(h.model.(Model).itemRows); got != 1 {
+			t.Errorf("search matched %d items, want 1", got)
+		}
+
+		h.press(tea.KeyEsc)
+		h.typeText("3")
+		h.snapshot("errors")
+
+		h.press(tea.KeyEsc)
+		h.typeText("o")
+		h.snapshot("title")
+		h.typeText("O")
+		if first, _ := h.model.(Model).selectedItem(); first.Title != "Video NEWVIDEO123" {
+			t.Errorf("first item by title Z–A = %q, want Video NEWVIDEO123", first.Title)
+		}
+
+		h.typeText("o")
+		h.snapshot("status")
+
+		h.typeText("31")
+		h.snapshot("combined")
+
+		h.typeText("/zzz")
+		h.press(tea.KeyEnter)
+		h.snapshot("nothing")
+	})
+}
+
+func TestNarrowTerminal(t *testing.T) {
+	h := newHarness(t, newDemoService("key"), 50, 16)
+	h.press(tea.KeyEnter, tea.KeyEnter)
+	h.typeText("https://youtu.be/NEWVIDEO123")
+	h.press(tea.KeyEnter)
+	h.tick(time.Minute)
+	h.snapshot("items")
+
+	h.send(tea.WindowSizeMsg{Width: 100, Height: 30})
+	h.size = sizeName(100, 30)
+	h.snapshot("resized")
+}
+
+func TestRetryItem(t *testing.T) {
+	eachSize(t, func(t *testing.T, width, height int) {
+		store, err := history.Open(filepath.Join(t.TempDir(), "history.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := newHarness(t, newDemoService("key"), width, height, WithHistory(store))
+		h.press(tea.KeyEnter, tea.KeyEnter)
+		h.typeText("https://youtu.be/" + failingVideo)
+		h.press(tea.KeyEnter)
+		h.tick(time.Minute)
+
+		h.typeText("r")
+		h.snapshot("retrying")
+		if got := store.Retries("pod001", "https://youtu.be/"+failingVideo); got != 1 {
+			t.Errorf("Retries = %d, want 1", got)
+		}
+
+		h.tick(time.Minute)
+		h.snapshot("failed")
+
+		h.press(tea.KeyDown, tea.KeyDown, tea.KeyDown)
+		h.typeText("r")
+		h.snapshot("succeeded")
+	})
+}
+
+func TestAPIErrors(t *testing.T) {
+	t.Run("unauthorized", func(t *testing.T) {
+		svc := newDemoService("key")
+		svc.ValidAPIKey = "other"
+		h := newHarness(t, svc, 80, 24)
+		h.expectState(ViewSetAPIKey)
+		h.snapshot("unauthorized")
+	})
+	t.Run("quota", func(t *testing.T) {
+		svc := newDemoService("key")
+		svc.SetLimit(1)
+		h := newHarness(t, svc, 80, 24)
+		h.press(tea.KeyEnter, tea.KeyEnter)
+		h.typeText("https://youtu.be/BIGVIDEO123")
+		h.press(tea.KeyEnter)
+		h.expectState(ViewEnterURL)
+		h.snapshot("quota")
+	})
+}
+
+func TestFatalError(t *testing.T) {
+	eachSize(t, func(t *testing.T, width, height int) {
+		h := newHarness(t, newDemoService("key"), width, height)
+		h.send(FatalErrorMsg{Err: errors.New("keyring unavailable")})
+		h.expectState(ViewFatalError)
+		if !h.quit {
+			t.Error("fatal error did not quit")
+		}
+		h.snapshot("error")
+	})
+}
+
+type stubProfiles struct {
+	names  []string
+	active string
+	svc    api.Service
+}
+
+func (p *stubProfiles) Names() []string { return p.names }
+func (p *stubProfiles) Active() string  { return p.active }
+
+func (p *stubProfiles) Switch(name string) (api.Service, error) {
+	p.active = name
+	return p.svc, nil
+}
+
+func TestSelectProfile(t *testing.T) {
+	eachSize(t, func(t *testing.T, width, height int) {
+		work := newDemoService("work-key")
+		profiles := &stubProfiles{names: []string{"default", "work"}, active: "default", svc: work}
+		h := newHarness(t, newDemoService("key"), width, height, WithProfiles(profiles))
+		h.press(tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+		h.expectState(ViewSelectProfile)
+		h.snapshot("list")
+
+		old := h.model.(Model).requestCtx
+		h.press(tea.KeyDown, tea.KeyEnter)
+		h.expectState(ViewMainMenu)
+		h.snapshot("switched")
+		if profiles.active != "work" {
+			t.Errorf("active profile = %q, want work", profiles.active)
+		}
+		if old.Err() == nil {
+			t.Error("requests of the old profile were not cancelled")
+		}
+	})
+}
+
+func TestQuitFromProfileList(t *testing.T) {
+	profiles := &stubProfiles{names: []string{"default", "work"}, active: "default"}
+	h := newHarness(t, newDemoService("key"), 80, 24, WithProfiles(profiles))
+	h.press(tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.expectState(ViewSelectProfile)
+
+	ctx := h.model.(Model).requestCtx
+	h.typeText("q")
+	if !h.quit {
+		t.Fatal("q did not quit")
+	}
+	if ctx.Err() == nil {
+		t.Error("quitting did not cancel outstanding requests")
+	}
+}
+
+func TestConfirmDuplicate(t *testing.T) {
+	eachSize(t, func(t *testing.T, width, height int) {
+		h := newHarness(t, newDemoService("key"), width, height)
+		h.press(tea.KeyEnter, tea.KeyEnter)
+		h.typeText("https://youtu.be/SxdOUGdseq4?si=share")
+		h.press(tea.KeyEnter)
+		h.expectState(ViewConfirmDuplicate)
+		h.snapshot("prompt")
+
+		h.typeText("n")
+		h.expectState(ViewEnterURL)
+		h.snapshot("skipped")
+
+		h.typeText("https://www.youtube.com/shorts/SxdOUGdseq4")
+		h.press(tea.KeyEnter)
+		h.expectState(ViewConfirmDuplicate)
+		h.typeText("y")
+		h.expectState(ViewItemsTable)
+	})
+}
+
+func TestDuplicateFromHistory(t *testing.T) {
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := newDemoService("key")
+	h := newHarness(t, svc, 80, 24, WithHistory(store))
+	h.press(tea.KeyEnter, tea.KeyEnter)
+	h.typeText("https://youtu.be/NEWVIDEO123")
+	h.press(tea.KeyEnter)
+	h.expectState(ViewItemsTable)
+	if _, ok := store.Lookup("pod001", "NEWVIDEO123"); !ok {
+		t.Fatal("submitted video was not recorded in the history")
+	}
+
+	// A server that doesn't report item URLs still gets caught by the
+	// history.
+	svc2 := fake.New("key")
+	talks := svc2.AddPodcast("Conference Talks")
+	svc2.AddItem(talks.ID, api.Item{Status: api.StatusSuccess, Title: "New"}, "", epoch)
+	h = newHarness(t, svc2, 80, 24, WithHistory(store))
+	h.press(tea.KeyEnter, tea.KeyEnter)
+	h.typeText("youtu.be/NEWVIDEO123")
+	h.press(tea.KeyEnter)
+	h.expectState(ViewConfirmDuplicate)
+}
+
+// fixtureFeeds serves every list from a saved feed.
+type fixtureFeeds string
+
+func (f fixtureFeeds) Fetch(ctx context.Context, list youtube.List) (*youtube.Feed, error) {
+	file, err := os.Open(string(f))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return youtube.ParseFeed(file)
+}
+
+func TestSelectVideos(t *testing.T) {
+	eachSize(t, func(t *testing.T, width, height int) {
+		svc := newDemoService("key")
+		h := newHarness(t, svc, width, height, WithFeeds(fixtureFeeds("../youtube/testdata/playlist.xml")))
+		h.press(tea.KeyEnter, tea.KeyEnter)
+		h.typeText("https://www.youtube.com/playlist?list=PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf")
+		h.press(tea.KeyEnter)
+		h.expectState(ViewSelectVideos)
+		h.snapshot("list")
+
+		h.press(tea.KeyDown)
+		h.press(tea.KeySpace)
+		h.snapshot("toggled")
+
+		h.press(tea.KeyEnter)
+		h.expectState(ViewItemsTable)
+		h.snapshot("added")
+
+		items, _ := svc.GetPodcastItems(context.Background(), "pod001")
+		if len(items) != 3 {
+			t.Errorf("podcast has %d items, want 3 after adding one video", len(items))
+		}
+	})
+}
+
+func TestSelectVideosNothingSelected(t *testing.T) {
+	h := newHarness(t, newDemoService("key"), 80, 24, WithFeeds(fixtureFeeds("../youtube/testdata/playlist.xml")))
+	h.press(tea.KeyEnter, tea.KeyEnter)
+	h.typeText("youtube.com/@talks")
+	h.press(tea.KeyEnter)
+	h.typeText("a")
+	h.typeText("a")
+	h.expectState(ViewSelectVideos)
+	h.press(tea.KeyEnter)
+	h.expectState(ViewSelectVideos)
+	h.snapshot("none")
+
+	h.press(tea.KeyEsc)
+	h.expectState(ViewEnterURL)
+}
@@ -1,91 +1,118 @@
 package ui
 
 import (
-	"fmt"
-	"sort"
+	"context"
+	"errors"
 	"time"
 
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/history"
 )
 
-func CheckAPIKey() tea.Msg {
-	_, err := api.GetApiKey()
-	return ApiKeyCheckedMsg{HasKey: err == nil}
+func CheckAPIKey(svc api.Service) tea.Cmd {
+	return func() tea.Msg {
+		_, err := svc.GetApiKey()
+		return ApiKeyCheckedMsg{HasKey: err == nil}
+	}
 }
 
-func LoadPodcasts() tea.Msg {
-	podcasts, err := api.ListPodcasts()
-	return PodcastsLoadedMsg{Podcasts: podcasts, Err: err}
+func LoadPodcasts(ctx context.Context, svc api.Service) tea.Cmd {
+	return func() tea.Msg {
+		podcasts, err := svc.ListPodcasts(ctx)
+		return PodcastsLoadedMsg{Podcasts: podcasts, Err: err}
+	}
 }
 
-func AddURL(podcastID, url string) tea.Cmd {
+func AddURL(ctx context.Context, svc api.Service, podcastID, url string) tea.Cmd {
 	return func() tea.Msg {
-		item, err := api.AddUrlToPodcast(podcastID, url)
-		return UrlAddedMsg{Item: item, Err: err}
+		item, err := svc.AddUrlToPodcast(ctx, podcastID, url)
+		return UrlAddedMsg{URL: url, Item: item, Err: err}
 	}
 }
 
-func LoadItems(podcastID string) tea.Cmd {
+// CheckDuplicate looks for url among the podcast's items and in the local
+// history. store may be nil.
+func CheckDuplicate(ctx context.Context, svc api.Service, store *history.Store, podcastID, url string) tea.Cmd {
 	return func() tea.Msg {
-		items, err := api.GetPodcastItems(podcastID)
-		return ItemsLoadedMsg{Items: items, Err: err}
+		items, err := svc.GetPodcastItems(ctx, podcastID)
+		if err != nil {
+			return DuplicateCheckedMsg{URL: url, Err: err}
+		}
+		dup, found := history.FindDuplicate(items, store, podcastID, url)
+		return DuplicateCheckedMsg{URL: url, Duplicate: dup, Found: found}
 	}
 }
 
-func LoadUsage() tea.Cmd {
+func LoadItems(ctx context.Context, svc api.Service, podcastID string) tea.Cmd {
 	return func() tea.Msg {
-		usage, err := api.GetUsage()
+		items, err := svc.GetPodcastItems(ctx, podcastID)
+		return ItemsLoadedMsg{PodcastID: podcastID, Items: items, Err: err}
+	}
+}
+
+func LoadUsage(ctx context.Context, svc api.Service) tea.Cmd {
+	return func() tea.Msg {
+		usage, err := svc.GetUsage(ctx)
 		return UsageLoadedMsg{Usage: usage, Err: err}
 	}
 }
 
-func parseCreatedTime(created string) time.Time {
-	if created == "" {
-		return time.Time{}
+// duplicateText describes when a duplicate video was first added.
+func duplicateText(dup history.Duplicate) string {
+	if dup.AddedAt.IsZero() {
+		return "added"
 	}
+	return "added on " + dup.AddedAt.Local().Format("Jan 2, 2006")
+}
 
-	layouts := []string{
-		time.RFC3339Nano,
-		time.RFC3339,
-		"2006-01-02 15:04:05.999Z",
-		"2006-01-02 15:04:05Z",
-		"2006-01-02 15:04:05.999Z07:00",
+// resetRequests cancels every request started from the previous view and
+// returns a fresh context for the requests of the next one.
+func (m *Model) resetRequests() context.Context {
+	if m.cancelRequests != nil {
+		m.cancelRequests()
 	}
-	for _, layout := range layouts {
-		if t, err := time.Parse(layout, created); err == nil {
-			return t
+	m.requestCtx, m.cancelRequests = context.WithCancel(context.Background())
+	return m.requestCtx
+}
+
+// isCanceled reports whether err is the result of resetRequests cancelling
+// a request, in which case the response belongs to a view the user has
+// already left and should be dropped.
+func isCanceled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
+
+// showAPIError records err for display. A missing or rejected API key sends
+// the user back to the API key screen, and hitting the storage limit gets
+// guidance instead of the raw server response.
+func (m *Model) showAPIError(err error) {
+	switch {
+	case api.IsUnauthorized(err):
+		m.Polling = false
+		m.HasAPIKey = false
+		m.Message = ""
+		m.State = ViewSetAPIKey
+		m.ApiKeyInput.Focus()
+		if errors.Is(err, api.ErrNoAPIKey) {
+			m.Error = "No API key set. Please enter your API key."
+		} else {
+			m.Error = "Your API key was rejected. Please enter a valid API key."
 		}
+	case api.IsQuotaExceeded(err):
+		m.Error = "You have reached your usage limit. Delete some episodes or upgrade your plan on ytrss.xyz."
+	default:
+		m.Error = err.Error()
 	}
-
-	return time.Time{}
 }
 
-func tick() tea.Cmd {
-	return tea.Tick(time.Second*3, func(t time.Time) tea.Msg {
+func (m Model) tick() tea.Cmd {
+	return tea.Tick(m.pollInterval, func(t time.Time) tea.Msg {
 		return TickMsg(t)
 	})
 }
 
-func formatBytes(bytes int) string {
-	const (
-		KB = 1024
-		MB = 1024 * KB
-		GB = 1024 * MB
-	)
-
-	if bytes >= GB {
-		return fmt.Sprintf("%.2f GB", float64(bytes)/float64(GB))
-	} else if bytes >= MB {
-		return fmt.Sprintf("%.2f MB", float64(bytes)/float64(MB))
-	} else if bytes >= KB {
-		return fmt.Sprintf("%.2f KB", float64(bytes)/float64(KB))
-	}
-	return fmt.Sprintf("%d B", bytes)
-}
-
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -93,56 +120,26 @@ func min(a, b int) int {
 	return b
 }
 
-func (m *Model) buildItemsTable() {
-	columns := []table.Column{
-		{Title: "Title", Width: 60},
-		{Title: "Status", Width: 20},
-		{Title: "Created", Width: 30},
-	}
-
-	sortedItems := make([]api.Item, len(m.Items))
-	copy(sortedItems, m.Items)
-	sort.Slice(sortedItems, func(i, j int) bool {
-		timeI := parseCreatedTime(sortedItems[i].Created)
-		timeJ := parseCreatedTime(sortedItems[j].Created)
+type columnWidths struct {
+	Title   int
+	Status  int
+	Created int
+}
 
-		if timeI.IsZero() && timeJ.IsZero() {
-			return false
-		}
-		if timeI.IsZero() {
-			return false
-		}
-		if timeJ.IsZero() {
-			return true
-		}
+// itemsChrome is the number of lines the items view needs besides the
+// table: the title, an error, a message, the search box, the filter summary
+// and the three lines of help.
+const itemsChrome = 11
 
-		return timeI.After(timeJ)
-	})
+func (m *Model) buildItemsTable() {
+	shown := m.itemFilter.apply(m.Items)
+	m.pruneSelection()
 
 	rows := []table.Row{}
-	for _, item := range sortedItems {
-		status := item.Status
-		switch item.Status {
-		case "CREATED":
-			status = m.Spinner.View() + " PROCESSING"
-		case "ERROR":
-			status = "❌ ERROR"
-		case "SUCCESS":
-			status = "✓ SUCCESS"
-		}
-
-		title := item.Title
-		if title == "" {
-			if item.Status == "CREATED" {
-				title = "Processing..."
-			} else {
-				title = "(No title)"
-			}
-		}
-
+	for _, item := range shown {
 		created := item.Created
 		if created != "" {
-			t := parseCreatedTime(created)
+			t := item.CreatedAt()
 			if !t.IsZero() {
 				created = t.Local().Format("Jan 2, 2006 3:04 PM")
 			}
@@ -150,55 +147,27 @@ func (m *Model) buildItemsTable() {
 			created = "-"
 		}
 
-		rows = append(rows, table.Row{title, status, created})
-	}
-
-	t := table.New(
-		table.WithColumns(columns),
-		table.WithRows(rows),
-		table.WithFocused(true),
-		table.WithHeight(min(len(rows)+2, 20)),
-	)
-
-	s := table.DefaultStyles()
-	s.Header = s.Header.
-		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("#7D56F4")).
-		BorderBottom(true).
-		Bold(true)
-
-	t.SetStyles(s)
-	m.ItemsTable = t
-}
-
-func (m *Model) buildPodcastTable() {
-	columns := []table.Column{
-		{Title: "Title", Width: 60},
-	}
-
-	rows := []table.Row{}
-	for _, p := range m.Podcasts {
-		rows = append(rows, table.Row{p.Title})
+		title := itemTitle(item)
+		if item.Hidden {
+			title += " (hidden)"
+		}
+		if m.itemSelected[item.ID] {
+			title = "● " + title
+		} else if len(m.itemSelected) > 0 {
+			title = "  " + title
+		}
+		rows = append(rows, table.Row{title, statusText(item.Status, m.Spinner.View()), created})
 	}
 
-	t := table.New(
-		table.WithColumns(columns),
-		table.WithRows(rows),
-		table.WithFocused(true),
-		table.WithHeight(10),
-	)
-
-	s := table.DefaultStyles()
-	s.Header = s.Header.
-		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("#7D56F4")).
-		BorderBottom(true).
-		Bold(true)
-	s.Selected = s.Selected.
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#7D56F4")).
-		Bold(false)
-
-	t.SetStyles(s)
-	m.PodcastTable = t
+	columns := itemColumns(m.Width, m.columnWidths)
+	m.itemFilter.markSorted(columns)
+
+	// The table is updated in place rather than rebuilt so the selected row
+	// and scroll position survive refreshes.
+	t := &m.ItemsTable
+	t.SetColumns(columns)
+	t.SetRows(rows)
+	t.SetHeight(m.tableHeight(len(rows), itemsChrome))
+	t.SetCursor(max(min(t.Cursor(), len(rows)-1), 0))
+	m.itemRows = shown
 }
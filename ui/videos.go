@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/history"
+	"github.com/lsherman98/yt-rss-cli/youtube"
+)
+
+// VideoChoice is a video from a playlist or channel feed, marked when it is
+// already in the podcast.
+type VideoChoice struct {
+	Entry     youtube.Entry
+	Duplicate history.Duplicate
+	Found     bool
+}
+
+type VideosLoadedMsg struct {
+	Title  string
+	Videos []VideoChoice
+	Err    error
+}
+
+type UrlsAddedMsg struct {
+	Added  []string
+	Items  []api.Item
+	Failed int
+	Err    error
+}
+
+// LoadVideos fetches a playlist or channel feed and checks each video
+// against the podcast's items and the local history, which may be nil.
+func LoadVideos(ctx context.Context, svc api.Service, feeds Feeds, store *history.Store, podcastID string, list youtube.List) tea.Cmd {
+	return func() tea.Msg {
+		feed, err := feeds.Fetch(ctx, list)
+		if err != nil {
+			return VideosLoadedMsg{Err: err}
+		}
+		items, err := svc.GetPodcastItems(ctx, podcastID)
+		if err != nil {
+			return VideosLoadedMsg{Err: err}
+		}
+
+		videos := make([]VideoChoice, len(feed.Entries))
+		for i, entry := range feed.Entries {
+			dup, found := history.FindDuplicate(items, store, podcastID, entry.Video.URL())
+			videos[i] = VideoChoice{Entry: entry, Duplicate: dup, Found: found}
+		}
+		return VideosLoadedMsg{Title: feed.Title, Videos: videos}
+	}
+}
+
+// AddURLs submits urls one after another. It stops early when the account's
+// quota is used up, since every remaining URL would fail the same way.
+func AddURLs(ctx context.Context, svc api.Service, podcastID string, urls []string) tea.Cmd {
+	return func() tea.Msg {
+		var msg UrlsAddedMsg
+		for i, url := range urls {
+			item, err := svc.AddUrlToPodcast(ctx, podcastID, url)
+			if err == nil {
+				msg.Added = append(msg.Added, url)
+				msg.Items = append(msg.Items, item)
+				continue
+			}
+			msg.Failed++
+			if msg.Err == nil {
+				msg.Err = err
+			}
+			if api.IsQuotaExceeded(err) || isCanceled(err) {
+				msg.Failed += len(urls) - i - 1
+				break
+			}
+		}
+		return msg
+	}
+}
+
+// showVideos switches to the checklist, with every video that isn't
+// already in the podcast selected.
+func (m *Model) showVideos(msg VideosLoadedMsg) {
+	m.videoTitle = msg.Title
+	m.videos = msg.Videos
+	m.videoSelected = make([]bool, len(msg.Videos))
+	for i, v := range msg.Videos {
+		m.videoSelected[i] = !v.Found
+	}
+	m.videoCursor = 0
+	m.Message = ""
+	m.State = ViewSelectVideos
+}
+
+func (m *Model) selectedVideoURLs() []string {
+	var urls []string
+	for i, v := range m.videos {
+		if m.videoSelected[i] {
+			urls = append(urls, v.Entry.Video.URL())
+		}
+	}
+	return urls
+}
+
+func (m *Model) updateSelectVideos(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		m.cancelRequests()
+		return tea.Quit
+	case "esc":
+		m.State = ViewEnterURL
+		return nil
+	case "up", "k":
+		if m.videoCursor > 0 {
+			m.videoCursor--
+		}
+	case "down", "j":
+		if m.videoCursor < len(m.videos)-1 {
+			m.videoCursor++
+		}
+	case " ", "x":
+		m.videoSelected[m.videoCursor] = !m.videoSelected[m.videoCursor]
+	case "a":
+		// Select everything unless everything is already selected.
+		all := true
+		for _, selected := range m.videoSelected {
+			all = all && selected
+		}
+		for i := range m.videoSelected {
+			m.videoSelected[i] = !all
+		}
+	case "enter":
+		urls := m.selectedVideoURLs()
+		if len(urls) == 0 {
+			m.Error = "Select at least one video"
+			return nil
+		}
+		m.Error = ""
+		m.Message = fmt.Sprintf("Adding %d video(s)...", len(urls))
+		return AddURLs(m.requestCtx, m.service, m.SelectedPodcast.ID, urls)
+	}
+	return nil
+}
+
+func (m Model) viewSelectVideos() string {
+	var s strings.Builder
+
+	s.WriteString(TitleStyle.Render(fmt.Sprintf("Add to %s from: %s", m.SelectedPodcast.Title, m.videoTitle)))
+	s.WriteString("\n")
+
+	selected := 0
+	for i, v := range m.videos {
+		if m.videoSelected[i] {
+			selected++
+		}
+
+		check := "[ ]"
+		if m.videoSelected[i] {
+			check = "[x]"
+		}
+		line := fmt.Sprintf("%s %s", check, v.Entry.Title)
+		if !v.Entry.Published.IsZero() {
+			line += " (" + v.Entry.Published.Local().Format("Jan 2, 2006") + ")"
+		}
+
+		if i == m.videoCursor {
+			line = lipgloss.NewStyle().Foreground(AccentColor).Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		if v.Found {
+			line += " " + MutedStyle.Render("— already "+duplicateText(v.Duplicate))
+		}
+		s.WriteString(m.truncate(line))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(MutedStyle.Render(fmt.Sprintf("%d of %d selected", selected, len(m.videos))))
+	s.WriteString("\n")
+	if m.Message != "" {
+		s.WriteString(MutedStyle.Render(m.Message))
+		s.WriteString("\n")
+	}
+	if m.Error != "" {
+		s.WriteString(ErrorStyle.Render("Error: " + m.Error))
+		s.WriteString("\n")
+	}
+	s.WriteString(HelpStyle.Render("↑/↓: Move • Space: Toggle • a: All/none • Enter: Add selected • Esc: Back"))
+
+	return s.String()
+}
@@ -0,0 +1,166 @@
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/history"
+)
+
+// EnvState overrides the location of the watch state file.
+const EnvState = "YTRSS_WATCH_STATE"
+
+// Store is the watch state file: the subscriptions and, for each, the video
+// IDs already handled and how often looking up a video's details failed.
+type Store struct {
+	path string
+
+	NextID        int                             `json:"next_id"`
+	Subscriptions []*Subscription                 `json:"subscriptions"`
+	Seen          map[string]map[string]time.Time `json:"seen"`
+	Failures      map[string]map[string]int       `json:"failures,omitempty"`
+}
+
+// Path returns the state file location: $YTRSS_WATCH_STATE, or watch.json
+// in the ytrss state directory.
+func Path() (string, error) {
+	if path := os.Getenv(EnvState); path != "" {
+		return path, nil
+	}
+	dir, err := history.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "watch.json"), nil
+}
+
+// Load opens the state file at Path.
+func Load() (*Store, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return Open(path)
+}
+
+// Open reads the state file at path. A missing file yields an empty store.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload re-reads the file, picking up subscriptions added by other
+// processes.
+func (s *Store) Reload() error {
+	fresh := Store{path: s.path}
+	data, err := os.ReadFile(s.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &fresh); err != nil {
+			return err
+		}
+	}
+	if fresh.Seen == nil {
+		fresh.Seen = make(map[string]map[string]time.Time)
+	}
+	if fresh.Failures == nil {
+		fresh.Failures = make(map[string]map[string]int)
+	}
+	*s = fresh
+	return nil
+}
+
+// Save writes the store back to its file.
+func (s *Store) Save() error {
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, "watch-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path)
+}
+
+// Add assigns sub the next ID and stores it.
+func (s *Store) Add(sub *Subscription) {
+	s.NextID++
+	sub.ID = strconv.Itoa(s.NextID)
+	s.Subscriptions = append(s.Subscriptions, sub)
+}
+
+// Get returns the subscription with the given ID.
+func (s *Store) Get(id string) (*Subscription, bool) {
+	for _, sub := range s.Subscriptions {
+		if sub.ID == id {
+			return sub, true
+		}
+	}
+	return nil, false
+}
+
+// Remove deletes a subscription and what was seen for it.
+func (s *Store) Remove(id string) bool {
+	for i, sub := range s.Subscriptions {
+		if sub.ID == id {
+			s.Subscriptions = append(s.Subscriptions[:i], s.Subscriptions[i+1:]...)
+			delete(s.Seen, id)
+			delete(s.Failures, id)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) seen(subID, videoID string) bool {
+	_, ok := s.Seen[subID][videoID]
+	return ok
+}
+
+func (s *Store) markSeen(subID, videoID string, at time.Time) {
+	if s.Seen[subID] == nil {
+		s.Seen[subID] = make(map[string]time.Time)
+	}
+	s.Seen[subID][videoID] = at.UTC()
+	s.forgetFailures(subID, videoID)
+}
+
+// recordFailure counts a failed details lookup and returns the number of
+// failures so far.
+func (s *Store) recordFailure(subID, videoID string) int {
+	if s.Failures[subID] == nil {
+		s.Failures[subID] = make(map[string]int)
+	}
+	s.Failures[subID][videoID]++
+	return s.Failures[subID][videoID]
+}
+
+func (s *Store) forgetFailures(subID, videoID string) {
+	delete(s.Failures[subID], videoID)
+	if len(s.Failures[subID]) == 0 {
+		delete(s.Failures, subID)
+	}
+}
@@ -0,0 +1,314 @@
+// Package watch subscribes podcasts to YouTube channels and playlists and
+// submits new uploads as they appear in the feeds.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api"
+	"github.com/lsherman98/yt-rss-cli/config"
+	"github.com/lsherman98/yt-rss-cli/history"
+	"github.com/lsherman98/yt-rss-cli/youtube"
+)
+
+// seenRetention is how long a video that has dropped out of its feed is
+// remembered. Feeds only list recent uploads, so old IDs rarely come back.
+const seenRetention = 180 * 24 * time.Hour
+
+// maxDetailsFailures is how many polls in a row may fail to look up a
+// video's details before the video is skipped. The lookup is retried until
+// then, since most failures are transient.
+const maxDetailsFailures = 5
+
+// Subscription ties a playlist or channel to a podcast.
+type Subscription struct {
+	ID           string    `json:"id" yaml:"id"`
+	PodcastID    string    `json:"podcast_id" yaml:"podcast_id"`
+	PodcastTitle string    `json:"podcast_title" yaml:"podcast_title"`
+	URL          string    `json:"url" yaml:"url"`
+	Title        string    `json:"title" yaml:"title"`
+	Channel      bool      `json:"channel,omitempty" yaml:"channel,omitempty"`
+	ListID       string    `json:"list_id" yaml:"list_id"`
+	Filter       Filter    `json:"filter" yaml:"filter"`
+	CreatedAt    time.Time `json:"created_at" yaml:"created_at"`
+	LastChecked  time.Time `json:"last_checked,omitempty" yaml:"last_checked,omitempty"`
+}
+
+func (s *Subscription) list() youtube.List {
+	if s.Channel {
+		return youtube.List{Kind: youtube.Channel, ID: s.ListID}
+	}
+	return youtube.List{Kind: youtube.Playlist, ID: s.ListID}
+}
+
+// Filter decides which new uploads are submitted. The zero Filter accepts
+// everything.
+type Filter struct {
+	// Match is a regular expression the title must match.
+	Match         string          `json:"match,omitempty" yaml:"match,omitempty"`
+	MinDuration   config.Duration `json:"min_duration,omitempty" yaml:"min_duration,omitempty"`
+	MaxDuration   config.Duration `json:"max_duration,omitempty" yaml:"max_duration,omitempty"`
+	ExcludeShorts bool            `json:"exclude_shorts,omitempty" yaml:"exclude_shorts,omitempty"`
+}
+
+// Validate checks that the pattern compiles and the durations make sense.
+func (f Filter) Validate() error {
+	if _, err := regexp.Compile(f.Match); err != nil {
+		return fmt.Errorf("invalid title pattern: %w", err)
+	}
+	if f.MinDuration < 0 || f.MaxDuration < 0 {
+		return errors.New("durations must not be negative")
+	}
+	if f.MaxDuration > 0 && f.MinDuration > f.MaxDuration {
+		return errors.New("minimum duration is longer than the maximum")
+	}
+	return nil
+}
+
+func (f Filter) needsDetails() bool {
+	return f.MinDuration > 0 || f.MaxDuration > 0 || f.ExcludeShorts
+}
+
+// rejectTitle returns why a title is filtered out, or "".
+func (f Filter) rejectTitle(title string) string {
+	if f.Match == "" {
+		return ""
+	}
+	if ok, _ := regexp.MatchString(f.Match, title); !ok {
+		return "title doesn't match " + f.Match
+	}
+	return ""
+}
+
+// rejectDetails returns why a video is filtered out, or "".
+func (f Filter) rejectDetails(d youtube.Details) string {
+	switch {
+	case f.ExcludeShorts && d.Short:
+		return "it is a Short"
+	case f.MinDuration > 0 && d.Duration < time.Duration(f.MinDuration):
+		return fmt.Sprintf("shorter than %s", time.Duration(f.MinDuration))
+	case f.MaxDuration > 0 && d.Duration > time.Duration(f.MaxDuration):
+		return fmt.Sprintf("longer than %s", time.Duration(f.MaxDuration))
+	}
+	return ""
+}
+
+// Feeds lists the videos of playlists and channels and looks up video
+// details. *youtube.FeedClient implements it.
+type Feeds interface {
+	Resolve(ctx context.Context, list youtube.List) (youtube.List, error)
+	Fetch(ctx context.Context, list youtube.List) (*youtube.Feed, error)
+	Details(ctx context.Context, video youtube.Video) (youtube.Details, error)
+}
+
+// Watcher polls the subscriptions in Store.
+type Watcher struct {
+	Service api.Service
+	Feeds   Feeds
+	Store   *Store
+	// History, if set, catches videos added by other means and records the
+	// ones the watcher adds.
+	History *history.Store
+	// Logf reports what happened to each new video.
+	Logf func(format string, args ...any)
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+func (w *Watcher) now() time.Time {
+	if w.Now != nil {
+		return w.Now()
+	}
+	return time.Now()
+}
+
+func (w *Watcher) logf(format string, args ...any) {
+	if w.Logf != nil {
+		w.Logf(format, args...)
+	}
+}
+
+// Subscribe validates sub, resolves its list and stores it. Unless
+// backfill is set, the videos currently in the feed are marked as seen so
+// only later uploads are submitted.
+func (w *Watcher) Subscribe(ctx context.Context, sub *Subscription, list youtube.List, backfill bool) error {
+	if err := sub.Filter.Validate(); err != nil {
+		return err
+	}
+	list, err := w.Feeds.Resolve(ctx, list)
+	if err != nil {
+		return err
+	}
+	feed, err := w.Feeds.Fetch(ctx, list)
+	if err != nil {
+		return err
+	}
+
+	if err := w.Store.Reload(); err != nil {
+		return err
+	}
+	sub.Channel = list.Kind == youtube.Channel
+	sub.ListID = list.ID
+	sub.Title = feed.Title
+	sub.CreatedAt = w.now().UTC()
+	w.Store.Add(sub)
+	if !backfill {
+		for _, entry := range feed.Entries {
+			w.Store.markSeen(sub.ID, entry.Video.ID, w.now())
+		}
+	}
+	return w.Store.Save()
+}
+
+// Run polls every interval until ctx is done. Errors are logged rather
+// than returned so one bad feed doesn't stop the others.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := w.Poll(ctx); err != nil && ctx.Err() == nil {
+			w.logf("%v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll checks every subscription once and submits the new uploads. The
+// store is reloaded first and saved after each subscription.
+func (w *Watcher) Poll(ctx context.Context) error {
+	if err := w.Store.Reload(); err != nil {
+		return err
+	}
+
+	items := make(map[string][]api.Item)
+	var errs []error
+	for _, sub := range w.Store.Subscriptions {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := w.poll(ctx, sub, items)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("[%s] %s: %w", sub.ID, sub.Title, err))
+		}
+		if err := w.Store.Save(); err != nil {
+			return err
+		}
+		if api.IsUnauthorized(err) {
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// poll handles one subscription. items caches each podcast's items for the
+// duplicate check. Videos that fail for a transient reason are left unseen
+// so the next poll retries them, except that a video whose details can't be
+// looked up is skipped after maxDetailsFailures polls.
+func (w *Watcher) poll(ctx context.Context, sub *Subscription, items map[string][]api.Item) error {
+	feed, err := w.Feeds.Fetch(ctx, sub.list())
+	if err != nil {
+		return err
+	}
+	sub.LastChecked = w.now().UTC()
+	w.prune(sub, feed)
+
+	// Feeds list the newest upload first; submit in upload order.
+	entries := slices.Clone(feed.Entries)
+	slices.Reverse(entries)
+
+	var errs []error
+	for _, entry := range entries {
+		if w.Store.seen(sub.ID, entry.Video.ID) {
+			continue
+		}
+		url := entry.Video.URL()
+
+		if reason := sub.Filter.rejectTitle(entry.Title); reason != "" {
+			w.skip(sub, entry, reason)
+			continue
+		}
+		if sub.Filter.needsDetails() {
+			details, err := w.Feeds.Details(ctx, entry.Video)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if n := w.Store.recordFailure(sub.ID, entry.Video.ID); n >= maxDetailsFailures {
+					w.skip(sub, entry, fmt.Sprintf("checking details failed %d times, last: %v", n, err))
+					continue
+				}
+				errs = append(errs, fmt.Errorf("checking %s: %w", url, err))
+				continue
+			}
+			if reason := sub.Filter.rejectDetails(details); reason != "" {
+				w.skip(sub, entry, reason)
+				continue
+			}
+		}
+
+		podcastItems, ok := items[sub.PodcastID]
+		if !ok {
+			podcastItems, err = w.Service.GetPodcastItems(ctx, sub.PodcastID)
+			if err != nil {
+				return err
+			}
+			items[sub.PodcastID] = podcastItems
+		}
+		if _, dup := history.FindDuplicate(podcastItems, w.History, sub.PodcastID, url); dup {
+			w.skip(sub, entry, "already in the podcast")
+			continue
+		}
+
+		item, err := w.Service.AddUrlToPodcast(ctx, sub.PodcastID, url)
+		if err != nil {
+			if api.IsQuotaExceeded(err) || api.IsUnauthorized(err) {
+				return err
+			}
+			errs = append(errs, fmt.Errorf("adding %s: %w", url, err))
+			continue
+		}
+		w.Store.markSeen(sub.ID, entry.Video.ID, w.now())
+		items[sub.PodcastID] = append(items[sub.PodcastID], item)
+		if w.History != nil {
+			if err := w.History.Record(sub.PodcastID, url, item.ID, w.now()); err != nil {
+				w.logf("[%s] saving history: %v", sub.ID, err)
+			}
+		}
+		w.logf("[%s] added %q (%s) to %s", sub.ID, entry.Title, url, sub.PodcastTitle)
+	}
+	return errors.Join(errs...)
+}
+
+func (w *Watcher) skip(sub *Subscription, entry youtube.Entry, reason string) {
+	w.Store.markSeen(sub.ID, entry.Video.ID, w.now())
+	w.logf("[%s] skipped %q: %s", sub.ID, entry.Title, reason)
+}
+
+// prune forgets videos that are no longer in the feed and were seen long
+// ago, and the failures of videos that are no longer in the feed.
+func (w *Watcher) prune(sub *Subscription, feed *youtube.Feed) {
+	inFeed := make(map[string]bool, len(feed.Entries))
+	for _, entry := range feed.Entries {
+		inFeed[entry.Video.ID] = true
+	}
+	cutoff := w.now().Add(-seenRetention)
+	for id, at := range w.Store.Seen[sub.ID] {
+		if !inFeed[id] && at.Before(cutoff) {
+			delete(w.Store.Seen[sub.ID], id)
+		}
+	}
+	for id := range w.Store.Failures[sub.ID] {
+		if !inFeed[id] {
+			w.Store.forgetFailures(sub.ID, id)
+		}
+	}
+}
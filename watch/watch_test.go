@@ -0,0 +1,252 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lsherman98/yt-rss-cli/api/fake"
+	"github.com/lsherman98/yt-rss-cli/config"
+	"github.com/lsherman98/yt-rss-cli/youtube"
+)
+
+// stubFeeds serves a single feed that tests can change between polls.
+type stubFeeds struct {
+	feed    youtube.Feed
+	details map[string]youtube.Details
+}
+
+func (f *stubFeeds) Resolve(ctx context.Context, list youtube.List) (youtube.List, error) {
+	if list.ID == "" {
+		list.ID = "UCsBjURrPoezykLs9EqgamOA"
+	}
+	return list, nil
+}
+
+func (f *stubFeeds) Fetch(ctx context.Context, list youtube.List) (*youtube.Feed, error) {
+	feed := f.feed
+	return &feed, nil
+}
+
+func (f *stubFeeds) Details(ctx context.Context, video youtube.Video) (youtube.Details, error) {
+	d, ok := f.details[video.ID]
+	if !ok {
+		return youtube.Details{}, errors.New("unavailable")
+	}
+	return d, nil
+}
+
+func entry(id, title string) youtube.Entry {
+	return youtube.Entry{Video: youtube.Video{ID: id}, Title: title}
+}
+
+func newWatcher(t *testing.T, feeds *stubFeeds) (*Watcher, *fake.Service, *[]string) {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), "watch.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := fake.New("key")
+	svc.AddPodcast("Talks")
+
+	var log []string
+	w := &Watcher{
+		Service: svc,
+		Feeds:   feeds,
+		Store:   store,
+		Logf: func(format string, args ...any) {
+			log = append(log, fmt.Sprintf(format, args...))
+		},
+	}
+	return w, svc, &log
+}
+
+func itemURLs(t *testing.T, svc *fake.Service) []string {
+	t.Helper()
+	items, err := svc.GetPodcastItems(context.Background(), "pod001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var urls []string
+	for _, item := range items {
+		urls = append(urls, item.URL)
+	}
+	return urls
+}
+
+func TestPollSubmitsOnlyNewUploads(t *testing.T) {
+	feeds := &stubFeeds{feed: youtube.Feed{Title: "Channel", Entries: []youtube.Entry{
+		entry("oldvideo001", "Old"),
+	}}}
+	w, svc, _ := newWatcher(t, feeds)
+	ctx := context.Background()
+
+	sub := &Subscription{PodcastID: "pod001", PodcastTitle: "Talks"}
+	if err := w.Subscribe(ctx, sub, youtube.List{Kind: youtube.Channel, Page: "/@talks"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if sub.ListID == "" || sub.Title != "Channel" {
+		t.Fatalf("subscription not resolved: %+v", sub)
+	}
+
+	feeds.feed.Entries = []youtube.Entry{entry("newvideo002", "Second"), entry("newvideo001", "First"), entry("oldvideo001", "Old")}
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	urls := itemURLs(t, svc)
+	if len(urls) != 2 {
+		t.Fatalf("items = %v, want the two new uploads", urls)
+	}
+
+	// Polling again doesn't resubmit anything.
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := itemURLs(t, svc); len(got) != 2 {
+		t.Errorf("second poll resubmitted: %v", got)
+	}
+}
+
+func TestPollBackfill(t *testing.T) {
+	feeds := &stubFeeds{feed: youtube.Feed{Entries: []youtube.Entry{entry("oldvideo001", "Old")}}}
+	w, svc, _ := newWatcher(t, feeds)
+	ctx := context.Background()
+	if err := w.Subscribe(ctx, &Subscription{PodcastID: "pod001"}, youtube.List{Kind: youtube.Playlist, ID: "PL0123456789"}, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := itemURLs(t, svc); len(got) != 1 {
+		t.Errorf("items = %v, want the backfilled video", got)
+	}
+}
+
+func TestPollFilters(t *testing.T) {
+	feeds := &stubFeeds{details: map[string]youtube.Details{
+		"talkvideo01": {Duration: 45 * time.Minute},
+		"shortvideo1": {Duration: 40 * time.Second, Short: true},
+		"longvideo01": {Duration: 5 * time.Hour},
+		"talkvideo02": {Duration: 10 * time.Minute},
+	}}
+	w, svc, log := newWatcher(t, feeds)
+	ctx := context.Background()
+	sub := &Subscription{PodcastID: "pod001", Filter: Filter{
+		Match:         "(?i)talk",
+		MinDuration:   config.Duration(time.Minute),
+		MaxDuration:   config.Duration(2 * time.Hour),
+		ExcludeShorts: true,
+	}}
+	if err := w.Subscribe(ctx, sub, youtube.List{Kind: youtube.Playlist, ID: "PL0123456789"}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	feeds.feed.Entries = []youtube.Entry{
+		entry("talkvideo01", "A Talk"),
+		entry("shortvideo1", "Talk teaser"),
+		entry("longvideo01", "Talk marathon"),
+		entry("musicvideo1", "Music video"),
+		entry("talkvideo03", "Talk without details"),
+	}
+	err := w.Poll(ctx)
+	if err == nil || !strings.Contains(err.Error(), "talkvideo03") {
+		t.Errorf("Poll error = %v, want the failed details lookup", err)
+	}
+	if got := itemURLs(t, svc); len(got) != 1 || !strings.HasSuffix(got[0], "talkvideo01") {
+		t.Errorf("items = %v, want only talkvideo01", got)
+	}
+	if len(*log) != 4 {
+		t.Errorf("log = %q, want one line per handled video", *log)
+	}
+
+	// The video whose details failed is retried; the filtered ones aren't.
+	feeds.details["talkvideo03"] = youtube.Details{Duration: 30 * time.Minute}
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := itemURLs(t, svc); len(got) != 2 {
+		t.Errorf("items = %v, want talkvideo03 added on retry", got)
+	}
+}
+
+func TestPollSkipsVideoAfterRepeatedDetailsFailures(t *testing.T) {
+	feeds := &stubFeeds{details: map[string]youtube.Details{}}
+	w, svc, log := newWatcher(t, feeds)
+	ctx := context.Background()
+	sub := &Subscription{PodcastID: "pod001", Filter: Filter{ExcludeShorts: true}}
+	if err := w.Subscribe(ctx, sub, youtube.List{Kind: youtube.Playlist, ID: "PL0123456789"}, false); err != nil {
+		t.Fatal(err)
+	}
+	feeds.feed.Entries = []youtube.Entry{entry("brokenvid01", "Broken")}
+
+	for i := 1; i < maxDetailsFailures; i++ {
+		if err := w.Poll(ctx); err == nil {
+			t.Fatalf("poll %d succeeded, want the details error", i)
+		}
+		if w.Store.seen(sub.ID, "brokenvid01") {
+			t.Fatalf("video skipped after %d failure(s)", i)
+		}
+	}
+
+	if err := w.Poll(ctx); err != nil {
+		t.Fatalf("poll %d error = %v, want the video skipped", maxDetailsFailures, err)
+	}
+	if !w.Store.seen(sub.ID, "brokenvid01") {
+		t.Error("video was not skipped")
+	}
+	if len(*log) != 1 || !strings.Contains((*log)[0], "checking details failed 5 times, last: unavailable") {
+		t.Errorf("log = %q, want the reason for skipping", *log)
+	}
+	if len(w.Store.Failures) != 0 {
+		t.Errorf("failures = %v, want them cleared once the video is skipped", w.Store.Failures)
+	}
+	if got := itemURLs(t, svc); len(got) != 0 {
+		t.Errorf("items = %v, want nothing added", got)
+	}
+
+	// The count survives restarts.
+	feeds.feed.Entries = []youtube.Entry{entry("brokenvid02", "Also broken")}
+	w.Poll(ctx)
+	reopened, err := Open(w.Store.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := reopened.Failures[sub.ID]["brokenvid02"]; n != 1 {
+		t.Errorf("saved failure count = %d, want 1", n)
+	}
+}
+
+func TestPollStopsOnQuota(t *testing.T) {
+	feeds := &stubFeeds{}
+	w, svc, _ := newWatcher(t, feeds)
+	ctx := context.Background()
+	if err := w.Subscribe(ctx, &Subscription{PodcastID: "pod001"}, youtube.List{Kind: youtube.Playlist, ID: "PL0123456789"}, false); err != nil {
+		t.Fatal(err)
+	}
+	svc.SetLimit(1)
+	feeds.feed.Entries = []youtube.Entry{entry("newvideo001", "New")}
+
+	if err := w.Poll(ctx); err == nil {
+		t.Fatal("Poll succeeded past the quota")
+	}
+	if w.Store.seen("1", "newvideo001") {
+		t.Error("video was marked seen although it wasn't added")
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	bad := []Filter{
+		{Match: "("},
+		{MinDuration: config.Duration(time.Hour), MaxDuration: config.Duration(time.Minute)},
+		{MinDuration: config.Duration(-time.Second)},
+	}
+	for _, f := range bad {
+		if err := f.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded", f)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var lengthPattern = regexp.MustCompile(`"lengthSeconds":"(\d+)"`)
+
+// Details are facts about a video that feeds don't include.
+type Details struct {
+	Duration time.Duration
+	Short    bool
+}
+
+// ParseDuration extracts the video length from the HTML of a watch page.
+func ParseDuration(r io.Reader) (time.Duration, error) {
+	page, err := io.ReadAll(io.LimitReader(r, 8<<20))
+	if err != nil {
+		return 0, err
+	}
+	m := lengthPattern.FindSubmatch(page)
+	if m == nil {
+		return 0, errors.New("video length not found on the watch page")
+	}
+	seconds, err := strconv.Atoi(string(m[1]))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// Details looks up a video's length on its watch page and whether it is a
+// Short. YouTube serves /shorts/ID for Shorts and redirects to the watch
+// page for every other video.
+func (c *FeedClient) Details(ctx context.Context, video Video) (Details, error) {
+	body, err := c.get(ctx, c.site()+"/watch?v="+video.ID)
+	if err != nil {
+		return Details{}, err
+	}
+	duration, err := ParseDuration(body)
+	body.Close()
+	if err != nil {
+		return Details{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "HEAD", c.site()+"/shorts/"+video.ID, nil)
+	if err != nil {
+		return Details{}, err
+	}
+	noRedirect := *c.httpClient()
+	noRedirect.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := noRedirect.Do(req)
+	if err != nil {
+		return Details{}, err
+	}
+	resp.Body.Close()
+
+	return Details{Duration: duration, Short: resp.StatusCode == http.StatusOK}, nil
+}
@@ -0,0 +1,75 @@
+package youtube
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	f, err := os.Open("testdata/watch_page.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	got, err := ParseDuration(f)
+	if err != nil || got != 754*time.Second {
+		t.Errorf("ParseDuration() = %v, %v; want 12m34s", got, err)
+	}
+
+	_, err = ParseDuration(strings.NewReader(`<html><script>var ytInitialPlayerResponse = {"videoDetails":{"isLive":true}};</script></html>`))
+	if err == nil || !strings.Contains(err.Error(), "video length not found") {
+		t.Errorf("ParseDuration() on a page without lengthSeconds error = %v", err)
+	}
+}
+
+func TestFeedClientDetails(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/watch", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("v") == "livestream0" {
+			w.Write([]byte("<html><body>Live now</body></html>"))
+			return
+		}
+		http.ServeFile(w, r, "testdata/watch_page.html")
+	})
+	mux.HandleFunc("/shorts/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/shorts/")
+		if id == "shortclip00" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.Redirect(w, r, "/watch?v="+id, http.StatusSeeOther)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := &FeedClient{SiteURL: srv.URL}
+	ctx := context.Background()
+
+	tests := []struct {
+		id    string
+		short bool
+	}{
+		{"shortclip00", true},
+		{"SxdOUGdseq4", false},
+	}
+	for _, tt := range tests {
+		got, err := client.Details(ctx, Video{ID: tt.id})
+		if err != nil {
+			t.Errorf("%s: Details failed: %v", tt.id, err)
+			continue
+		}
+		if got.Short != tt.short || got.Duration != 754*time.Second {
+			t.Errorf("%s: Details() = %+v, want Short %v and 12m34s", tt.id, got, tt.short)
+		}
+	}
+
+	if _, err := client.Details(ctx, Video{ID: "livestream0"}); err == nil {
+		t.Error("Details accepted a watch page without a length")
+	}
+}
@@ -0,0 +1,243 @@
+package youtube
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrNotList is returned for links that are neither a playlist nor a
+// channel.
+var ErrNotList = errors.New("not a playlist or channel link")
+
+var (
+	playlistIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{10,}$`)
+	channelIDPattern  = regexp.MustCompile(`^UC[A-Za-z0-9_-]{22}$`)
+	namePattern       = regexp.MustCompile(`^@?[A-Za-z0-9_.%-]+$`)
+
+	// The channel page names its ID in the canonical link and in the
+	// embedded player config; either is enough.
+	channelPagePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`<link rel="canonical" href="https://www\.youtube\.com/channel/(UC[A-Za-z0-9_-]{22})"`),
+		regexp.MustCompile(`"(?:channelId|externalId)":"(UC[A-Za-z0-9_-]{22})"`),
+	}
+)
+
+// ListKind says what a List points at.
+type ListKind int
+
+const (
+	Playlist ListKind = iota
+	Channel
+)
+
+// List is a playlist or channel whose videos can be listed from its feed.
+// Channels linked by handle (@name), custom URL (/c/name) or legacy user
+// name have an empty ID until resolved by FeedClient.
+type List struct {
+	Kind ListKind
+	ID   string
+	// Page is the path of the channel page, such as /@name, to resolve the
+	// ID from when ID is empty.
+	Page string
+}
+
+// ParseList recognizes playlist links (youtube.com/playlist?list=...) and
+// channel links (/channel/ID, /@handle, /c/name, /user/name, optionally
+// followed by a tab such as /videos). Watch links that carry a list
+// parameter are videos; see Parse.
+func ParseList(raw string) (List, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return List{}, ErrNotList
+	}
+
+	switch strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.") {
+	case "youtube.com", "m.youtube.com", "music.youtube.com":
+	default:
+		return List{}, ErrNotList
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case segments[0] == "playlist":
+		id := u.Query().Get("list")
+		if !playlistIDPattern.MatchString(id) {
+			return List{}, fmt.Errorf("%w: missing or invalid list parameter", ErrNotList)
+		}
+		return List{Kind: Playlist, ID: id}, nil
+
+	case segments[0] == "channel" && len(segments) > 1:
+		if !channelIDPattern.MatchString(segments[1]) {
+			return List{}, fmt.Errorf("%w: %q is not a valid channel ID", ErrNotList, segments[1])
+		}
+		return List{Kind: Channel, ID: segments[1]}, nil
+
+	case strings.HasPrefix(segments[0], "@") && namePattern.MatchString(segments[0]):
+		return List{Kind: Channel, Page: "/" + segments[0]}, nil
+
+	case (segments[0] == "c" || segments[0] == "user") && len(segments) > 1 && namePattern.MatchString(segments[1]):
+		return List{Kind: Channel, Page: "/" + segments[0] + "/" + segments[1]}, nil
+	}
+	return List{}, ErrNotList
+}
+
+// Feed is a playlist or channel's Atom feed. YouTube only includes the most
+// recent 15 videos.
+type Feed struct {
+	Title   string
+	Entries []Entry
+}
+
+// Entry is one video in a Feed.
+type Entry struct {
+	Video     Video
+	Title     string
+	Published time.Time
+}
+
+type atomFeed struct {
+	Title   string `xml:"http://www.w3.org/2005/Atom title"`
+	Entries []struct {
+		VideoID   string    `xml:"http://www.youtube.com/xml/schemas/2015 videoId"`
+		Title     string    `xml:"http://www.w3.org/2005/Atom title"`
+		Published time.Time `xml:"http://www.w3.org/2005/Atom published"`
+	} `xml:"http://www.w3.org/2005/Atom entry"`
+}
+
+// ParseFeed decodes a YouTube Atom feed. Entries without a valid video ID
+// are skipped.
+func ParseFeed(r io.Reader) (*Feed, error) {
+	var atom atomFeed
+	if err := xml.NewDecoder(r).Decode(&atom); err != nil {
+		return nil, fmt.Errorf("parsing feed: %w", err)
+	}
+
+	feed := &Feed{Title: strings.TrimSpace(atom.Title)}
+	for _, e := range atom.Entries {
+		if !videoIDPattern.MatchString(e.VideoID) {
+			continue
+		}
+		feed.Entries = append(feed.Entries, Entry{
+			Video:     Video{ID: e.VideoID},
+			Title:     strings.TrimSpace(e.Title),
+			Published: e.Published,
+		})
+	}
+	return feed, nil
+}
+
+// ResolveChannelID extracts the channel ID from the HTML of a channel page.
+func ResolveChannelID(r io.Reader) (string, error) {
+	page, err := io.ReadAll(io.LimitReader(r, 4<<20))
+	if err != nil {
+		return "", err
+	}
+	for _, pattern := range channelPagePatterns {
+		if m := pattern.FindSubmatch(page); m != nil {
+			return string(m[1]), nil
+		}
+	}
+	return "", errors.New("channel ID not found on the channel page")
+}
+
+// DefaultSiteURL is where channel pages and feeds are fetched from.
+const DefaultSiteURL = "https://www.youtube.com"
+
+// FeedClient fetches playlist and channel feeds.
+type FeedClient struct {
+	HTTPClient *http.Client
+	// SiteURL defaults to DefaultSiteURL.
+	SiteURL string
+}
+
+// NewFeedClient returns a client with a 30 second timeout.
+func NewFeedClient() *FeedClient {
+	return &FeedClient{HTTPClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (c *FeedClient) site() string {
+	if c.SiteURL == "" {
+		return DefaultSiteURL
+	}
+	return c.SiteURL
+}
+
+// Resolve fills in the ID of a channel linked by handle or name from its
+// page. Lists that already have an ID are returned unchanged.
+func (c *FeedClient) Resolve(ctx context.Context, list List) (List, error) {
+	if list.ID != "" {
+		return list, nil
+	}
+
+	body, err := c.get(ctx, c.site()+list.Page)
+	if err != nil {
+		return List{}, fmt.Errorf("resolving channel: %w", err)
+	}
+	defer body.Close()
+	list.ID, err = ResolveChannelID(body)
+	if err != nil {
+		return List{}, fmt.Errorf("resolving channel %s: %w", list.Page, err)
+	}
+	list.Page = ""
+	return list, nil
+}
+
+// Fetch returns the videos in a playlist or channel, resolving the channel
+// ID from its page first if needed.
+func (c *FeedClient) Fetch(ctx context.Context, list List) (*Feed, error) {
+	list, err := c.Resolve(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	if list.Kind == Playlist {
+		query.Set("playlist_id", list.ID)
+	} else {
+		query.Set("channel_id", list.ID)
+	}
+	body, err := c.get(ctx, c.site()+"/feeds/videos.xml?"+query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("fetching feed: %w", err)
+	}
+	defer body.Close()
+	return ParseFeed(body)
+}
+
+func (c *FeedClient) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+func (c *FeedClient) get(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, errors.New("not found, the playlist or channel may be private or deleted")
+		}
+		return nil, fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,129 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseList(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want List
+	}{
+		{"https://www.youtube.com/playlist?list=PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf&si=abc", List{Kind: Playlist, ID: "PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf"}},
+		{"music.youtube.com/playlist?list=OLAK5uy_kxyz0123456789", List{Kind: Playlist, ID: "OLAK5uy_kxyz0123456789"}},
+		{"https://www.youtube.com/channel/UCsBjURrPoezykLs9EqgamOA/videos", List{Kind: Channel, ID: "UCsBjURrPoezykLs9EqgamOA"}},
+		{"https://m.youtube.com/@talks", List{Kind: Channel, Page: "/@talks"}},
+		{"https://www.youtube.com/c/TalksChannel/featured", List{Kind: Channel, Page: "/c/TalksChannel"}},
+		{"youtube.com/user/talks", List{Kind: Channel, Page: "/user/talks"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseList(tt.raw)
+		if err != nil {
+			t.Errorf("ParseList(%q) failed: %v", tt.raw, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseList(%q) = %+v, want %+v", tt.raw, got, tt.want)
+		}
+	}
+
+	for _, raw := range []string{
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ&list=PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf",
+		"https://youtu.be/dQw4w9WgXcQ",
+		"https://www.youtube.com/playlist",
+		"https://www.youtube.com/channel/nope",
+		"https://example.com/playlist?list=PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf",
+	} {
+		if _, err := ParseList(raw); !errors.Is(err, ErrNotList) {
+			t.Errorf("ParseList(%q) error = %v, want ErrNotList", raw, err)
+		}
+	}
+}
+
+func TestParseFeed(t *testing.T) {
+	f, err := os.Open("testdata/playlist.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	feed, err := ParseFeed(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feed.Title != "Conference Talks 2025" {
+		t.Errorf("Title = %q", feed.Title)
+	}
+	want := []Entry{
+		{Video{"SxdOUGdseq4"}, "Simple Made Easy", time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC)},
+		{Video{"lKXe3HUG2l4"}, "The Mess We're In", time.Date(2025, 2, 20, 18, 0, 0, 0, time.UTC)},
+		{Video{"oytL881p-nQ"}, "Hammock Driven Development", time.Date(2025, 2, 10, 18, 0, 0, 0, time.UTC)},
+	}
+	if len(feed.Entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(feed.Entries), len(want))
+	}
+	for i, e := range feed.Entries {
+		if e.Video != want[i].Video || e.Title != want[i].Title || !e.Published.Equal(want[i].Published) {
+			t.Errorf("entry %d = %+v, want %+v", i, e, want[i])
+		}
+	}
+}
+
+func TestParseFeedSkipsInvalidEntries(t *testing.T) {
+	f, err := os.Open("testdata/channel.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	feed, err := ParseFeed(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(feed.Entries) != 1 || feed.Entries[0].Title != "Hammock Time (Live)" {
+		t.Errorf("entries = %+v, want only the trimmed valid entry", feed.Entries)
+	}
+}
+
+func TestParseFeedRejectsGarbage(t *testing.T) {
+	if _, err := ParseFeed(strings.NewReader("<html>")); err == nil {
+		t.Error("ParseFeed accepted truncated input")
+	}
+}
+
+func TestFeedClientResolvesHandle(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/@talks", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/channel_page.html")
+	})
+	mux.HandleFunc("/feeds/videos.xml", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("channel_id"); got != "UCsBjURrPoezykLs9EqgamOA" {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, "testdata/channel.xml")
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := &FeedClient{SiteURL: srv.URL}
+	feed, err := client.Fetch(context.Background(), List{Kind: Channel, Page: "/@talks"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feed.Title != "Talks Channel" || len(feed.Entries) != 1 {
+		t.Errorf("feed = %+v", feed)
+	}
+
+	_, err = client.Fetch(context.Background(), List{Kind: Playlist, ID: "PLmissing0000"})
+	if err == nil || !strings.Contains(err.Error(), "private or deleted") {
+		t.Errorf("missing playlist error = %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+// Package youtube recognizes YouTube video links and rewrites them into a
+// single canonical form.
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrNotYouTube is returned for input that is not a YouTube link.
+	ErrNotYouTube = errors.New("not a YouTube link")
+	// ErrNoVideoID is returned for YouTube links that don't point at a
+	// single video, such as a channel or the home page.
+	ErrNoVideoID = errors.New("no video ID in link")
+)
+
+var videoIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// Video is a YouTube video identified by its 11 character ID.
+type Video struct {
+	ID string
+}
+
+// URL returns the canonical watch URL of the video.
+func (v Video) URL() string {
+	return "https://www.youtube.com/watch?v=" + v.ID
+}
+
+// Parse extracts the video from a link. It understands watch, youtu.be,
+// shorts, live, embed, music.youtube.com and mobile links, with or without
+// a scheme. Query parameters other than the video ID, such as si, feature
+// and t, are ignored.
+func Parse(raw string) (Video, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Video{}, fmt.Errorf("%w: empty input", ErrNotYouTube)
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return Video{}, fmt.Errorf("%w: %q is not a web address", ErrNotYouTube, strings.TrimPrefix(raw, "https://"))
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	var id string
+	switch host {
+	case "youtu.be":
+		id = segments[0]
+	case "youtube.com", "m.youtube.com", "music.youtube.com", "youtube-nocookie.com":
+		switch segments[0] {
+		case "watch":
+			id = u.Query().Get("v")
+		case "shorts", "live", "embed", "v", "e":
+			if len(segments) > 1 {
+				id = segments[1]
+			}
+		}
+	default:
+		return Video{}, fmt.Errorf("%w: expected a youtube.com or youtu.be address, got %s", ErrNotYouTube, u.Hostname())
+	}
+
+	if id == "" {
+		return Video{}, fmt.Errorf("%w: paste a link to a video, playlist or channel", ErrNoVideoID)
+	}
+	if !videoIDPattern.MatchString(id) {
+		return Video{}, fmt.Errorf("%w: %q is not a valid video ID", ErrNoVideoID, id)
+	}
+	return Video{ID: id}, nil
+}
+
+// Canonicalize returns the canonical watch URL for a video link.
+func Canonicalize(raw string) (string, error) {
+	v, err := Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	return v.URL(), nil
+}
@@ -0,0 +1,60 @@
+package youtube
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	const id = "dQw4w9WgXcQ"
+	valid := []string{
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		"https://youtube.com/watch?v=dQw4w9WgXcQ&t=42s&feature=share",
+		"http://m.youtube.com/watch?feature=youtu.be&v=dQw4w9WgXcQ",
+		"https://music.youtube.com/watch?v=dQw4w9WgXcQ&list=RDAMVM",
+		"https://youtu.be/dQw4w9WgXcQ?si=abcdef123",
+		"youtu.be/dQw4w9WgXcQ",
+		"www.youtube.com/watch?v=dQw4w9WgXcQ",
+		"https://www.youtube.com/shorts/dQw4w9WgXcQ",
+		"https://www.youtube.com/live/dQw4w9WgXcQ?si=x",
+		"https://www.youtube.com/embed/dQw4w9WgXcQ?start=10",
+		"https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ",
+		"  https://WWW.YOUTUBE.COM/watch?v=dQw4w9WgXcQ  ",
+	}
+	for _, raw := range valid {
+		v, err := Parse(raw)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", raw, err)
+			continue
+		}
+		if v.ID != id {
+			t.Errorf("Parse(%q).ID = %q, want %q", raw, v.ID, id)
+		}
+		if got, want := v.URL(), "https://www.youtube.com/watch?v="+id; got != want {
+			t.Errorf("Parse(%q).URL() = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want error
+	}{
+		{"", ErrNotYouTube},
+		{"hello world", ErrNotYouTube},
+		{"https://vimeo.com/12345", ErrNotYouTube},
+		{"https://notyoutube.com/watch?v=dQw4w9WgXcQ", ErrNotYouTube},
+		{"ftp://youtube.com/watch?v=dQw4w9WgXcQ", ErrNotYouTube},
+		{"https://www.youtube.com/", ErrNoVideoID},
+		{"https://www.youtube.com/@somechannel", ErrNoVideoID},
+		{"https://www.youtube.com/watch?v=short", ErrNoVideoID},
+		{"https://youtu.be/", ErrNoVideoID},
+		{"https://www.youtube.com/shorts/", ErrNoVideoID},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.raw); !errors.Is(err, tt.want) {
+			t.Errorf("Parse(%q) error = %v, want %v", tt.raw, err, tt.want)
+		}
+	}
+}